@@ -0,0 +1,51 @@
+package cge
+
+import "fmt"
+
+// isAlias reports whether the next declaration is an alias declaration ('type NAME = TYPE').
+func (p *parser) isAlias() bool {
+	next := p.skipPrefix(p.current)
+	return p.tokens[next].Type == TYPE && p.tokens[next+1].Type == IDENTIFIER && p.tokens[next+2].Type == EQUAL
+}
+
+func (p *parser) alias() (Object, error) {
+	comments, attributes, deprecation, err := p.prefix(false)
+	if err != nil {
+		return Object{}, err
+	}
+	start := startOf(attributes, p.peek())
+
+	if !p.match(TYPE) {
+		return Object{}, p.newError(CodeSyntax, "Expect 'type' keyword.", false)
+	}
+
+	if !p.match(IDENTIFIER) {
+		return Object{}, p.newError(CodeSyntax, "Expect identifier after 'type' keyword.", false)
+	}
+	name := p.previous()
+
+	if previous, ok := p.types[name.Lexeme]; ok {
+		return Object{}, p.newRedeclarationError(fmt.Sprintf("Type '%s' already defined.", name.Lexeme), name, previous, false)
+	}
+	p.types[name.Lexeme] = name
+
+	if !p.match(EQUAL) {
+		return Object{}, p.newError(CodeSyntax, "Expect '=' after type name.", false)
+	}
+
+	valueType, err := p.propertyType()
+	if err != nil {
+		return Object{}, err
+	}
+
+	return Object{
+		Comments:      commentLexemes(comments),
+		CommentTokens: comments,
+		Type:          ALIAS,
+		Name:          name,
+		ValueType:     valueType,
+		Attributes:    attributes,
+		Deprecated:    deprecation,
+		Range:         p.rangeFrom(start),
+	}, nil
+}
@@ -0,0 +1,225 @@
+package cge
+
+import "fmt"
+
+// Position is a location in a CGE file. Line and Column are zero-based and Column counts runes.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String returns the position in the format used by error messages: '[file:line:column]' with one-based lines and columns.
+func (p Position) String() string {
+	return formatPosition(p)
+}
+
+// Range is the part of a CGE file which is covered by a node or token. End is exclusive.
+// The range of nodes which don't appear in the source (e.g. the implicit config object) is the zero Range.
+type Range struct {
+	Start Position
+	End   Position
+}
+
+// Position returns the position of the first character of the token.
+func (t Token) Position() Position {
+	return Position{
+		File:   t.File,
+		Line:   t.Line,
+		Column: t.Column,
+	}
+}
+
+// Range returns the range covered by the lexeme of the token.
+func (t Token) Range() Range {
+	end := t.Position()
+	end.Column += len([]rune(t.Lexeme))
+	return Range{
+		Start: t.Position(),
+		End:   end,
+	}
+}
+
+// ObjectType is the kind of a top-level declaration.
+type ObjectType string
+
+const (
+	ConfigObject  ObjectType = "config"
+	CommandObject ObjectType = "command"
+	EventObject   ObjectType = "event"
+	TypeObject    ObjectType = "type"
+	EnumObject    ObjectType = "enum"
+	UnionObject   ObjectType = "union"
+	AliasObject   ObjectType = "alias"
+	ConstObject   ObjectType = "const"
+)
+
+// Kind returns the kind of the object. Unlike Type, it is independent of the tokens of the grammar.
+func (o Object) Kind() ObjectType {
+	switch o.Type {
+	case CONFIG:
+		return ConfigObject
+	case COMMAND:
+		return CommandObject
+	case EVENT:
+		return EventObject
+	case TYPE:
+		return TypeObject
+	case ENUM:
+		return EnumObject
+	case UNION:
+		return UnionObject
+	case ALIAS:
+		return AliasObject
+	case CONST:
+		return ConstObject
+	default:
+		return ObjectType(o.Type)
+	}
+}
+
+// File is the syntax tree of a CGE file. Objects contains the declarations of all imported files as well.
+type File struct {
+	Metadata Metadata
+	Objects  []Object
+	// Warnings contains the warnings of the main file and all imported files, e.g. unknown attributes or CGE version mismatches.
+	// They don't prevent code generation and can be ignored.
+	Warnings []Diagnostic
+	// Range covers the whole main file.
+	Range Range
+}
+
+// Node is implemented by all nodes of the syntax tree: *File, *Object, *Property, *PropertyType, *Literal, *Attribute and *Constraint.
+type Node interface {
+	// SourceRange returns the range of the node in the source code.
+	SourceRange() Range
+}
+
+func (f *File) SourceRange() Range         { return f.Range }
+func (o *Object) SourceRange() Range       { return o.Range }
+func (p *Property) SourceRange() Range     { return p.Range }
+func (t *PropertyType) SourceRange() Range { return t.Range }
+func (l *Literal) SourceRange() Range      { return l.Range }
+func (a *Attribute) SourceRange() Range    { return a.Range }
+func (c *Constraint) SourceRange() Range   { return c.Range }
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children of node with the visitor w,
+// followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the syntax tree in depth-first order, visiting the children of every node in the order they appear in the source:
+// It starts by calling v.Visit(node); node must not be nil.
+// The children of an object are its attributes, extends, value type, properties and value.
+// The children of a property are its attributes, type, constraints, default value and enum value.
+// The children of a property type are its key type, generic type and type arguments.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *File:
+		for i := range n.Objects {
+			Walk(v, &n.Objects[i])
+		}
+	case *Object:
+		walkAttributes(v, n.Attributes)
+		if n.Extends != nil {
+			Walk(v, n.Extends)
+		}
+		if n.ValueType != nil {
+			Walk(v, n.ValueType)
+		}
+		for i := range n.Properties {
+			Walk(v, &n.Properties[i])
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *Property:
+		walkAttributes(v, n.Attributes)
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+		for i := range n.Constraints {
+			Walk(v, &n.Constraints[i])
+		}
+		if n.Default != nil {
+			Walk(v, n.Default)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *PropertyType:
+		if n.Key != nil {
+			Walk(v, n.Key)
+		}
+		if n.Generic != nil {
+			Walk(v, n.Generic)
+		}
+		for _, a := range n.TypeArguments {
+			Walk(v, a)
+		}
+	case *Attribute:
+		for i := range n.Arguments {
+			Walk(v, &n.Arguments[i])
+		}
+	case *Constraint:
+		Walk(v, &n.Value)
+	case *Literal:
+	default:
+		panic(fmt.Sprintf("cge.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkAttributes(v Visitor, attributes []Attribute) {
+	for i := range attributes {
+		Walk(v, &attributes[i])
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the syntax tree in depth-first order like Walk.
+// It starts by calling f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the children of node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// commentLexemes returns the text of all comment tokens.
+func commentLexemes(tokens []Token) []string {
+	var comments []string
+	for _, t := range tokens {
+		comments = append(comments, t.Lexeme)
+	}
+	return comments
+}
+
+// startOf returns the start of a declaration, property or enum value, which is either its first attribute or next.
+func startOf(attributes []Attribute, next Token) Position {
+	if len(attributes) > 0 {
+		return attributes[0].Range.Start
+	}
+	return next.Position()
+}
+
+// rangeFrom returns the range from start to the end of the last consumed token.
+func (p *parser) rangeFrom(start Position) Range {
+	return Range{
+		Start: start,
+		End:   p.previous().Range().End,
+	}
+}
@@ -0,0 +1,140 @@
+package cge
+
+import "fmt"
+
+// Attribute is an annotation in front of a declaration, property or enum value, e.g. '@deprecated("Use 'move' instead.")'.
+// Attributes which are unknown to the parser are kept, so that external tools can define their own.
+type Attribute struct {
+	// Name is the IDENTIFIER token after '@'.
+	Name Token
+	// Arguments contains the string and number literals passed to the attribute.
+	Arguments []Literal
+	// Range starts at '@' and includes the arguments.
+	Range Range
+}
+
+// knownAttributes maps the names of all attributes understood by the parser to a function which validates their arguments.
+var knownAttributes = map[string]func(p *parser, attribute Attribute, inBlock bool) error{
+	"deprecated": checkDeprecatedAttribute,
+}
+
+// Attribute returns the attribute with the specified name or nil if the object doesn't have such an attribute.
+func (o Object) Attribute(name string) *Attribute {
+	return findAttribute(o.Attributes, name)
+}
+
+// Attribute returns the attribute with the specified name or nil if the property doesn't have such an attribute.
+func (p Property) Attribute(name string) *Attribute {
+	return findAttribute(p.Attributes, name)
+}
+
+func findAttribute(attributes []Attribute, name string) *Attribute {
+	for i, a := range attributes {
+		if a.Name.Lexeme == name {
+			return &attributes[i]
+		}
+	}
+	return nil
+}
+
+// prefix parses the comments and attributes in front of a declaration, property or enum value.
+func (p *parser) prefix(inBlock bool) ([]Token, []Attribute, *Deprecation, error) {
+	var comments []Token
+	var attributes []Attribute
+	for p.peek().Type == COMMENT || p.peek().Type == AT {
+		if p.match(COMMENT) {
+			comments = append(comments, p.previous())
+			continue
+		}
+		p.match(AT)
+		attribute, err := p.attribute(inBlock)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if findAttribute(attributes, attribute.Name.Lexeme) != nil {
+			return nil, nil, nil, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Duplicate attribute '%s'.", attribute.Name.Lexeme), attribute.Name, inBlock)
+		}
+		if check, ok := knownAttributes[attribute.Name.Lexeme]; ok {
+			err = check(p, attribute, inBlock)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		} else {
+			p.warnAt(CodeUnknownAttribute, fmt.Sprintf("Unknown attribute '%s'.", attribute.Name.Lexeme), attribute.Name)
+		}
+		attributes = append(attributes, attribute)
+	}
+	return comments, attributes, deprecation(attributes), nil
+}
+
+func (p *parser) attribute(inBlock bool) (Attribute, error) {
+	start := p.previous().Position()
+	if !p.match(IDENTIFIER) {
+		return Attribute{}, p.newError(CodeSyntax, "Expect attribute name after '@'.", inBlock)
+	}
+	attribute := Attribute{
+		Name: p.previous(),
+	}
+
+	if p.match(OPEN_PAREN) {
+		for p.peek().Type != EOF && p.peek().Type != CLOSE_PAREN {
+			if p.peek().Type != STRING_LITERAL && p.peek().Type != NUMBER {
+				return Attribute{}, p.newError(CodeSyntax, "Expect string or number as attribute argument.", inBlock)
+			}
+			argument, err := p.literal()
+			if err != nil {
+				return Attribute{}, err
+			}
+			attribute.Arguments = append(attribute.Arguments, *argument)
+			if !p.match(COMMA) {
+				break
+			}
+		}
+		if !p.match(CLOSE_PAREN) {
+			return Attribute{}, p.newError(CodeSyntax, "Expect ')' after attribute arguments.", inBlock)
+		}
+	}
+	attribute.Range = p.rangeFrom(start)
+
+	return attribute, nil
+}
+
+// skipPrefix returns the index of the first token after the comments and attributes starting at index next.
+func (p *parser) skipPrefix(next int) int {
+	for {
+		switch p.tokens[next].Type {
+		case COMMENT:
+			next++
+		case AT:
+			next++
+			if p.tokens[next].Type == IDENTIFIER {
+				next++
+			}
+			if p.tokens[next].Type == OPEN_PAREN {
+				for p.tokens[next].Type != CLOSE_PAREN && p.tokens[next].Type != EOF {
+					next++
+				}
+				if p.tokens[next].Type == CLOSE_PAREN {
+					next++
+				}
+			}
+		default:
+			return next
+		}
+	}
+}
+
+// warnAt adds a warning at token to the warnings of the parser.
+func (p *parser) warnAt(code Code, message string, token Token) {
+	line := []rune{}
+	if lines, ok := p.sources[token.File]; ok && token.Line >= 0 {
+		line = lines[token.Line]
+	}
+	p.warnings = append(p.warnings, Diagnostic{
+		Severity: SeverityWarning,
+		Code:     code,
+		Message:  message,
+		Range:    token.Range(),
+		Source:   string(line),
+	})
+}
@@ -0,0 +1,66 @@
+package cge
+
+import "fmt"
+
+func (p *parser) constant() (Object, error) {
+	comments, attributes, deprecation, err := p.prefix(false)
+	if err != nil {
+		return Object{}, err
+	}
+	start := startOf(attributes, p.peek())
+
+	if !p.match(CONST) {
+		return Object{}, p.newError(CodeSyntax, "Expect 'const' keyword.", false)
+	}
+
+	if !p.match(IDENTIFIER) {
+		return Object{}, p.newError(CodeSyntax, "Expect identifier after 'const' keyword.", false)
+	}
+	name := p.previous()
+
+	if previous, ok := p.constants[name.Lexeme]; ok {
+		return Object{}, p.newRedeclarationError(fmt.Sprintf("Constant '%s' already defined.", name.Lexeme), name, previous, false)
+	}
+	p.constants[name.Lexeme] = name
+
+	if !p.match(COLON) {
+		return Object{}, p.newError(CodeSyntax, "Expect ':' after constant name.", false)
+	}
+
+	if !p.match(STRING, BOOL, INT32, INT64, FLOAT32, FLOAT64, UINT32, UINT64, IDENTIFIER) {
+		return Object{}, p.newError(CodeSyntax, "Expect primitive or enum type after ':'.", false)
+	}
+	valueType := &PropertyType{
+		Token: p.previous(),
+		Range: p.previous().Range(),
+	}
+	if valueType.Token.Type == IDENTIFIER {
+		p.accessedTypeIdentifiers = append(p.accessedTypeIdentifiers, valueType.Token)
+	}
+
+	if !p.match(EQUAL) {
+		return Object{}, p.newError(CodeSyntax, "Expect '=' after constant type.", false)
+	}
+
+	value, err := p.literal()
+	if err != nil {
+		return Object{}, err
+	}
+
+	err = p.checkLiteral(value, valueType)
+	if err != nil {
+		return Object{}, err
+	}
+
+	return Object{
+		Comments:      commentLexemes(comments),
+		CommentTokens: comments,
+		Type:          CONST,
+		Name:          name,
+		ValueType:     valueType,
+		Value:         value,
+		Attributes:    attributes,
+		Deprecated:    deprecation,
+		Range:         p.rangeFrom(start),
+	}, nil
+}
@@ -0,0 +1,151 @@
+package cge
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Constraint restricts the valid values of a property.
+type Constraint struct {
+	// Name is the IDENTIFIER token of the constraint name (min, max, min_len, max_len, pattern, min_items or max_items).
+	Name  Token
+	Value Literal
+	// Range starts at the name and ends after the value.
+	Range Range
+}
+
+func (c Constraint) String() string {
+	return fmt.Sprintf("%s=%s", c.Name.Lexeme, c.Value)
+}
+
+// Constraint returns the constraint with the specified name or nil if the property doesn't have such a constraint.
+func (p Property) Constraint(name string) *Constraint {
+	for i, c := range p.Constraints {
+		if c.Name.Lexeme == name {
+			return &p.Constraints[i]
+		}
+	}
+	return nil
+}
+
+func (p *parser) constraints(propertyType *PropertyType) ([]Constraint, error) {
+	constraints := make([]Constraint, 0)
+	for p.peek().Type != EOF && p.peek().Type != CLOSE_SQUARE {
+		constraint, err := p.constraint(propertyType)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range constraints {
+			if c.Name.Lexeme == constraint.Name.Lexeme {
+				return nil, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Duplicate constraint '%s'.", c.Name.Lexeme), constraint.Name, true)
+			}
+		}
+		constraints = append(constraints, constraint)
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	if !p.match(CLOSE_SQUARE) {
+		return nil, p.newError(CodeSyntax, "Expect ']' after constraints.", true)
+	}
+
+	err := p.checkConstraintBounds(constraints, "min", "max")
+	if err == nil {
+		err = p.checkConstraintBounds(constraints, "min_len", "max_len")
+	}
+	if err == nil {
+		err = p.checkConstraintBounds(constraints, "min_items", "max_items")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return constraints, nil
+}
+
+func (p *parser) constraint(propertyType *PropertyType) (Constraint, error) {
+	if !p.match(IDENTIFIER) {
+		return Constraint{}, p.newError(CodeSyntax, "Expect constraint name.", true)
+	}
+	name := p.previous()
+
+	if !p.match(EQUAL) {
+		return Constraint{}, p.newError(CodeSyntax, "Expect '=' after constraint name.", true)
+	}
+
+	value, err := p.literal()
+	if err != nil {
+		return Constraint{}, err
+	}
+
+	t := propertyType.Token.Type
+	switch name.Lexeme {
+	case "min", "max":
+		if t != INT32 && t != INT64 && t != UINT32 && t != UINT64 && t != FLOAT32 && t != FLOAT64 {
+			return Constraint{}, p.newErrorAt(CodeInvalidConstraint, fmt.Sprintf("Constraint '%s' is only allowed for number types.", name.Lexeme), name, true)
+		}
+		err = p.checkLiteral(value, propertyType)
+		if err != nil {
+			return Constraint{}, err
+		}
+	case "min_len", "max_len", "pattern":
+		if t != STRING {
+			return Constraint{}, p.newErrorAt(CodeInvalidConstraint, fmt.Sprintf("Constraint '%s' is only allowed for strings.", name.Lexeme), name, true)
+		}
+		if name.Lexeme == "pattern" {
+			if value.Token.Type != STRING_LITERAL {
+				return Constraint{}, p.newErrorAt(CodeInvalidValue, "Expect string after 'pattern='.", value.Token, true)
+			}
+			if _, err := regexp.Compile(value.Value); err != nil {
+				return Constraint{}, p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Invalid pattern: %s", err), value.Token, true)
+			}
+		} else if err := p.checkCount(name, value); err != nil {
+			return Constraint{}, err
+		}
+	case "min_items", "max_items":
+		if t != LIST && t != MAP {
+			return Constraint{}, p.newErrorAt(CodeInvalidConstraint, fmt.Sprintf("Constraint '%s' is only allowed for lists and maps.", name.Lexeme), name, true)
+		}
+		if err := p.checkCount(name, value); err != nil {
+			return Constraint{}, err
+		}
+	default:
+		return Constraint{}, p.newErrorAt(CodeInvalidConstraint, fmt.Sprintf("Unknown constraint '%s'.", name.Lexeme), name, true)
+	}
+
+	return Constraint{
+		Name:  name,
+		Value: *value,
+		Range: p.rangeFrom(name.Position()),
+	}, nil
+}
+
+func (p *parser) checkCount(name Token, value *Literal) error {
+	if _, err := strconv.ParseUint(value.Value, 10, 32); value.Token.Type != NUMBER || err != nil {
+		return p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Expect non-negative integer after '%s='.", name.Lexeme), value.Token, true)
+	}
+	return nil
+}
+
+func (p *parser) checkConstraintBounds(constraints []Constraint, minName, maxName string) error {
+	var min, max *Constraint
+	for i, c := range constraints {
+		if c.Name.Lexeme == minName {
+			min = &constraints[i]
+		} else if c.Name.Lexeme == maxName {
+			max = &constraints[i]
+		}
+	}
+	if min == nil || max == nil {
+		return nil
+	}
+
+	minValue, err1 := strconv.ParseFloat(min.Value.Value, 64)
+	maxValue, err2 := strconv.ParseFloat(max.Value.Value, 64)
+	if err1 == nil && err2 == nil && minValue > maxValue {
+		return p.newErrorAt(CodeInvalidValue, fmt.Sprintf("'%s' must not be greater than '%s'.", minName, maxName), min.Name, true)
+	}
+	return nil
+}
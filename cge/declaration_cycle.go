@@ -25,6 +25,10 @@ func (p *parser) detectDeclarationCycles() {
 
 	detector.objects = make(map[string]*declCycleObj, len(detector.parser.objects))
 	for _, o := range detector.parser.objects {
+		// events and commands can't be referenced, so they must not shadow types with the same name
+		if o.Type == EVENT || o.Type == COMMAND {
+			continue
+		}
 		detector.objects[o.Name.Lexeme] = &declCycleObj{
 			o: o,
 		}
@@ -40,7 +44,7 @@ func (d *declarationCycleDetector) find() {
 }
 
 func (d *declarationCycleDetector) check(obj *declCycleObj) {
-	if obj.o.Type != TYPE {
+	if obj.o.Type != TYPE && obj.o.Type != UNION && obj.o.Type != ALIAS {
 		return
 	}
 
@@ -52,7 +56,7 @@ func (d *declarationCycleDetector) check(obj *declCycleObj) {
 			for j, o := range d.stack[i:len(d.stack)] {
 				names[j] = o.o.Name.Lexeme
 			}
-			d.parser.errors = append(d.parser.errors, d.parser.newErrorAt(fmt.Sprintf("Declaration cycle detected: %s", strings.Join(names, "->")), obj.o.Name, false))
+			d.parser.errors = append(d.parser.errors, d.parser.newErrorAt(CodeDeclarationCycle, fmt.Sprintf("Declaration cycle detected: %s", strings.Join(names, "->")), obj.o.Name, false))
 			d.popFromStack()
 		}
 		return
@@ -60,16 +64,45 @@ func (d *declarationCycleDetector) check(obj *declCycleObj) {
 
 	d.pushToStack(obj)
 
-	for _, p := range obj.o.Properties {
-		o, ok := d.objects[p.Type.Token.Lexeme]
+	if obj.o.Type == ALIAS {
+		d.checkPropertyType(obj.o.ValueType)
+	}
+
+	if obj.o.Extends != nil {
+		o, ok := d.objects[obj.o.Extends.Token.Lexeme]
 		if ok {
 			d.check(o)
 		}
 	}
 
+	for _, p := range obj.o.Properties {
+		d.checkPropertyType(p.Type)
+	}
+
 	d.popFromStack()
 }
 
+func (d *declarationCycleDetector) checkPropertyType(propertyType *PropertyType) {
+	if propertyType.Token.Type != IDENTIFIER {
+		return
+	}
+	o, ok := d.objects[propertyType.Token.Lexeme]
+	if !ok {
+		return
+	}
+	d.check(o)
+
+	// the type arguments of a generic type are part of the declaration if they are used directly as property types
+	if len(o.o.TypeParameters) != len(propertyType.TypeArguments) {
+		return
+	}
+	for i, argument := range propertyType.TypeArguments {
+		if embedsTypeParameter(d.objects, o.o, i, make(map[string]struct{})) {
+			d.checkPropertyType(argument)
+		}
+	}
+}
+
 func (d *declarationCycleDetector) pushToStack(obj *declCycleObj) {
 	d.stack = append(d.stack, obj)
 }
@@ -0,0 +1,35 @@
+package cge
+
+// Deprecation marks a declaration, property or enum value as deprecated.
+// It is created from the '@deprecated' attribute.
+type Deprecation struct {
+	// Token is the 'deprecated' identifier of the attribute.
+	Token Token
+	// Reason is the optional explanation passed to the attribute. It is empty if no reason was given.
+	Reason string
+}
+
+func checkDeprecatedAttribute(p *parser, attribute Attribute, inBlock bool) error {
+	if len(attribute.Arguments) > 1 {
+		return p.newErrorAt(CodeInvalidValue, "'@deprecated' expects at most one argument.", attribute.Arguments[1].Token, inBlock)
+	}
+	if len(attribute.Arguments) == 1 && attribute.Arguments[0].Token.Type != STRING_LITERAL {
+		return p.newErrorAt(CodeInvalidValue, "Expect deprecation reason to be a string.", attribute.Arguments[0].Token, inBlock)
+	}
+	return nil
+}
+
+// deprecation returns the deprecation described by the '@deprecated' attribute in attributes or nil if there is no such attribute.
+func deprecation(attributes []Attribute) *Deprecation {
+	attribute := findAttribute(attributes, "deprecated")
+	if attribute == nil {
+		return nil
+	}
+	deprecation := &Deprecation{
+		Token: attribute.Name,
+	}
+	if len(attribute.Arguments) > 0 {
+		deprecation.Reason = attribute.Arguments[0].Value
+	}
+	return deprecation
+}
@@ -0,0 +1,179 @@
+package cge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Code identifies the kind of a diagnostic. Codes are stable and can be used to filter or look up diagnostics.
+type Code string
+
+const (
+	CodeUnknown             Code = "unknown"
+	CodeSyntax              Code = "syntax"
+	CodeInvalidCharacter    Code = "invalid-character"
+	CodeUnterminatedString  Code = "unterminated-string"
+	CodeUnterminatedComment Code = "unterminated-comment"
+	CodeInvalidVersion      Code = "invalid-version"
+	CodeVersionMismatch     Code = "version-mismatch"
+	CodeImport              Code = "import"
+	CodeImportCycle         Code = "import-cycle"
+	CodeRedeclaration       Code = "redeclaration"
+	CodeDuplicate           Code = "duplicate"
+	CodeUndefinedType       Code = "undefined-type"
+	CodeUndefinedEvent      Code = "undefined-event"
+	CodeUndefinedEnumValue  Code = "undefined-enum-value"
+	CodeInvalidValue        Code = "invalid-value"
+	CodeInvalidDefault      Code = "invalid-default"
+	CodeInvalidConstraint   Code = "invalid-constraint"
+	CodeInvalidMapKey       Code = "invalid-map-key"
+	CodeTypeArguments       Code = "type-arguments"
+	CodeInvalidUnion        Code = "invalid-union"
+	CodeInvalidExtends      Code = "invalid-extends"
+	CodeInvalidScope        Code = "invalid-scope"
+	CodeInvalidResponse     Code = "invalid-response"
+	CodeDeclarationCycle    Code = "declaration-cycle"
+	CodeUnknownAttribute    Code = "unknown-attribute"
+	CodeLossyConversion     Code = "lossy-conversion"
+)
+
+// Diagnostic is an error or warning with a location in a CGE file.
+type Diagnostic struct {
+	Severity Severity
+	Code     Code
+	Message  string
+	// Range is the zero Range if the diagnostic doesn't refer to a location in a file.
+	Range Range
+	// Source is the text of the line at Range.Start. It is used to render the diagnostic.
+	Source string
+	// Related contains additional locations which help to understand the diagnostic, e.g. the previous declaration of a name.
+	Related []RelatedLocation
+}
+
+// RelatedLocation is an additional location of a diagnostic.
+type RelatedLocation struct {
+	Message string
+	Range   Range
+}
+
+// Error returns the diagnostic rendered with RenderPlain.
+func (d Diagnostic) Error() string {
+	return RenderPlain(d)
+}
+
+// AsDiagnostic converts an error returned by Parse into a diagnostic.
+// Errors which don't have a location in a CGE file result in a diagnostic with the code CodeUnknown.
+func AsDiagnostic(err error) Diagnostic {
+	var diagnostic Diagnostic
+	if errors.As(err, &diagnostic) {
+		return diagnostic
+	}
+	var parseErr ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.Diagnostic()
+	}
+	var scanErr ScanError
+	if errors.As(err, &scanErr) {
+		return scanErr.Diagnostic()
+	}
+	return Diagnostic{
+		Severity: SeverityError,
+		Code:     CodeUnknown,
+		Message:  err.Error(),
+	}
+}
+
+// RenderColored renders a diagnostic for terminal output. The location of the diagnostic is underlined using ANSI escape codes.
+// The severity is not included.
+func RenderColored(d Diagnostic) string {
+	return renderDiagnostic(d, true)
+}
+
+// RenderPlain renders a diagnostic like RenderColored without any escape codes. The location of the diagnostic is marked with '^'.
+func RenderPlain(d Diagnostic) string {
+	return renderDiagnostic(d, false)
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type jsonRelatedLocation struct {
+	Message string       `json:"message"`
+	File    string       `json:"file,omitempty"`
+	Start   jsonPosition `json:"start"`
+	End     jsonPosition `json:"end"`
+}
+
+type jsonDiagnostic struct {
+	Severity Severity              `json:"severity"`
+	Code     Code                  `json:"code"`
+	Message  string                `json:"message"`
+	File     string                `json:"file,omitempty"`
+	Start    *jsonPosition         `json:"start,omitempty"`
+	End      *jsonPosition         `json:"end,omitempty"`
+	Related  []jsonRelatedLocation `json:"related,omitempty"`
+}
+
+// RenderJSON encodes the diagnostics as a JSON array. Lines and columns are one-based like in the text output.
+func RenderJSON(diagnostics []Diagnostic) ([]byte, error) {
+	result := make([]jsonDiagnostic, len(diagnostics))
+	for i, d := range diagnostics {
+		result[i] = jsonDiagnostic{
+			Severity: d.Severity,
+			Code:     d.Code,
+			Message:  d.Message,
+			File:     d.Range.Start.File,
+		}
+		if d.Range != (Range{}) {
+			start, end := jsonPositionOf(d.Range.Start), jsonPositionOf(d.Range.End)
+			result[i].Start = &start
+			result[i].End = &end
+		}
+		for _, r := range d.Related {
+			result[i].Related = append(result[i].Related, jsonRelatedLocation{
+				Message: r.Message,
+				File:    r.Range.Start.File,
+				Start:   jsonPositionOf(r.Range.Start),
+				End:     jsonPositionOf(r.Range.End),
+			})
+		}
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+func jsonPositionOf(position Position) jsonPosition {
+	return jsonPosition{
+		Line:   position.Line + 1,
+		Column: position.Column + 1,
+	}
+}
+
+func renderDiagnostic(d Diagnostic, color bool) string {
+	if d.Range == (Range{}) && d.Source == "" {
+		return fmt.Sprintf("%s (%s)", d.Message, d.Code)
+	}
+
+	columnEnd := d.Range.End.Column
+	if d.Range.End.Line != d.Range.Start.Line {
+		columnEnd = len([]rune(d.Source))
+	}
+	if columnEnd <= d.Range.Start.Column {
+		columnEnd = d.Range.Start.Column + 1
+	}
+
+	text := generateErrorText(fmt.Sprintf("%s (%s)", d.Message, d.Code), d.Range.Start.File, []rune(d.Source), d.Range.Start.Line, d.Range.Start.Column, columnEnd, color)
+	for _, r := range d.Related {
+		text = fmt.Sprintf("%s\n%s %s", text, formatPosition(r.Range.Start), r.Message)
+	}
+	return text
+}
@@ -0,0 +1,668 @@
+package cge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Change is a difference between two versions of a CGE file.
+type Change struct {
+	// Breaking is true if clients which were generated from the old file might not work with a server using the new file or vice versa.
+	Breaking bool
+	// Kind is the kind of the changed object. It is empty for changes of the metadata.
+	Kind ObjectType
+	// Object is the name of the changed object. It is empty for changes of the metadata or the config.
+	Object string
+	// Member is the name of the changed property, enum value or union member. It is empty if the object itself changed.
+	Member  string
+	Message string
+	// Old and New are the ranges of the changed element in the old and new file.
+	// They are the zero Range if the element doesn't exist in the respective file.
+	Old Range
+	New Range
+}
+
+// usage describes in which direction values of a type are sent.
+type usage int
+
+const (
+	// sentToClients types are used in events or the config.
+	sentToClients usage = 1 << iota
+	// sentToServer types are used in commands or the config.
+	sentToServer
+)
+
+type differ struct {
+	oldObjects map[string]Object
+	newObjects map[string]Object
+	oldUsage   map[string]usage
+	newUsage   map[string]usage
+	changes    []Change
+}
+
+// Diff compares two versions of a CGE file object by object and classifies every change as breaking or compatible.
+// Changes which only affect comments, formatting or the order of declarations are ignored.
+//
+// Removing or renaming any declaration, property, enum value or union member is a breaking change,
+// as well as changing a type or value. Adding a declaration is compatible.
+// Whether adding a property, enum value or union member or changing the optionality, default value or constraints of a property is breaking
+// depends on whether the object is sent to clients (events) or to the server (commands), or both (the config and types used by both).
+func Diff(old, new *File) []Change {
+	d := &differ{
+		oldObjects: objectsByKey(old.Objects),
+		newObjects: objectsByKey(new.Objects),
+		oldUsage:   typeUsage(old.Objects),
+		newUsage:   typeUsage(new.Objects),
+		changes:    make([]Change, 0),
+	}
+
+	if old.Metadata.Name != new.Metadata.Name {
+		d.add(Change{
+			Breaking: true,
+			Message:  fmt.Sprintf("The game name changed from '%s' to '%s'.", old.Metadata.Name, new.Metadata.Name),
+			Old:      old.Metadata.NameToken.Range(),
+			New:      new.Metadata.NameToken.Range(),
+		})
+	}
+	if old.Metadata.CGEVersion != new.Metadata.CGEVersion {
+		d.add(Change{
+			Message: fmt.Sprintf("The CGE version changed from %s to %s.", old.Metadata.CGEVersion, new.Metadata.CGEVersion),
+			Old:     old.Metadata.VersionToken.Range(),
+			New:     new.Metadata.VersionToken.Range(),
+		})
+	}
+
+	removed := make([]Object, 0)
+	for _, o := range old.Objects {
+		n, ok := d.newObjects[objectKey(o)]
+		if !ok {
+			removed = append(removed, o)
+			continue
+		}
+		d.compareObjects(o, n)
+	}
+
+	added := make([]Object, 0)
+	for _, o := range new.Objects {
+		if _, ok := d.oldObjects[objectKey(o)]; !ok {
+			added = append(added, o)
+		}
+	}
+
+	d.compareRemovedAndAdded(removed, added)
+
+	return d.changes
+}
+
+// compareRemovedAndAdded reports all removed and added objects. A removed object is reported as renamed
+// if there is an added object of the same kind with the same definition.
+func (d *differ) compareRemovedAndAdded(removed, added []Object) {
+	renamed := make(map[int]bool)
+	for _, o := range removed {
+		index := -1
+		if len(o.Properties) > 0 || o.ValueType != nil {
+			for i, a := range added {
+				if !renamed[i] && objectKey(a) != objectKey(o) && o.Kind() == a.Kind() && signature(o, d.oldObjects) == signature(a, d.newObjects) {
+					index = i
+					break
+				}
+			}
+		}
+		if index >= 0 {
+			renamed[index] = true
+			d.add(Change{
+				Breaking: true,
+				Kind:     o.Kind(),
+				Object:   o.Name.Lexeme,
+				Message:  fmt.Sprintf("%s was renamed to '%s'.", capitalize(describe(o)), added[index].Name.Lexeme),
+				Old:      o.Range,
+				New:      added[index].Range,
+			})
+			continue
+		}
+		d.add(Change{
+			Breaking: true,
+			Kind:     o.Kind(),
+			Object:   o.Name.Lexeme,
+			Message:  fmt.Sprintf("%s was removed.", capitalize(describe(o))),
+			Old:      o.Range,
+		})
+	}
+
+	for i, o := range added {
+		if renamed[i] {
+			continue
+		}
+		d.add(Change{
+			Kind:    o.Kind(),
+			Object:  o.Name.Lexeme,
+			Message: fmt.Sprintf("%s was added.", capitalize(describe(o))),
+			New:     o.Range,
+		})
+	}
+}
+
+func (d *differ) compareObjects(old, new Object) {
+	if old.Kind() != new.Kind() {
+		d.add(Change{
+			Breaking: true,
+			Kind:     new.Kind(),
+			Object:   new.Name.Lexeme,
+			Message:  fmt.Sprintf("'%s' changed from %s to %s.", new.Name.Lexeme, article(old.Kind()), article(new.Kind())),
+			Old:      old.Range,
+			New:      new.Range,
+		})
+		return
+	}
+
+	d.compareDeprecation(old, new, nil, nil)
+
+	switch new.Type {
+	case CONFIG, COMMAND, EVENT, TYPE:
+		if len(old.TypeParameters) != len(new.TypeParameters) {
+			d.addObjectChange(old, new, true, "changed the number of its type parameters from %d to %d", len(old.TypeParameters), len(new.TypeParameters))
+		}
+		oldExtends, newExtends := "", ""
+		if old.Extends != nil {
+			oldExtends = old.Extends.Token.Lexeme
+		}
+		if new.Extends != nil {
+			newExtends = new.Extends.Token.Lexeme
+		}
+		if oldExtends != newExtends {
+			switch {
+			case oldExtends == "":
+				d.addObjectChange(old, new, true, "now extends '%s'", newExtends)
+			case newExtends == "":
+				d.addObjectChange(old, new, true, "no longer extends '%s'", oldExtends)
+			default:
+				d.addObjectChange(old, new, true, "now extends '%s' instead of '%s'", newExtends, oldExtends)
+			}
+		}
+		if old.Scope.Lexeme != new.Scope.Lexeme {
+			d.addObjectChange(old, new, true, "changed its scope from %s to %s", scopeName(old.Scope), scopeName(new.Scope))
+		}
+		d.compareResponses(old, new)
+		d.compareProperties(old, new)
+	case ENUM:
+		d.compareEnums(old, new)
+	case UNION:
+		if old.Discriminator.Lexeme != new.Discriminator.Lexeme {
+			d.addObjectChange(old, new, true, "changed its discriminator from '%s' to '%s'", old.Discriminator.Lexeme, new.Discriminator.Lexeme)
+		}
+		d.compareUnionMembers(old, new)
+	case ALIAS:
+		d.compareTypes(old, new, nil, nil, old.ValueType, new.ValueType)
+	case CONST:
+		d.compareTypes(old, new, nil, nil, old.ValueType, new.ValueType)
+		if old.Value.String() != new.Value.String() {
+			d.addObjectChange(old, new, true, "changed its value from %s to %s", old.Value, new.Value)
+		}
+	}
+}
+
+func (d *differ) compareResponses(old, new Object) {
+	for _, r := range old.Responses {
+		if !containsToken(new.Responses, r.Lexeme) {
+			d.addObjectChange(old, new, true, "no longer responds with '%s'", r.Lexeme)
+		}
+	}
+	for _, r := range new.Responses {
+		if !containsToken(old.Responses, r.Lexeme) {
+			d.addObjectChange(old, new, false, "now responds with '%s'", r.Lexeme)
+		}
+	}
+}
+
+func (d *differ) compareProperties(old, new Object) {
+	usage := d.usage(new)
+	oldProperties := propertiesByName(old.Properties)
+	newProperties := propertiesByName(new.Properties)
+
+	for i, p := range old.Properties {
+		n, ok := newProperties[p.Name]
+		if !ok {
+			d.addMemberChange(old, new, &old.Properties[i], nil, true, "was removed")
+			continue
+		}
+		d.compareProperty(old, new, &old.Properties[i], n, usage)
+	}
+
+	for i, p := range new.Properties {
+		if _, ok := oldProperties[p.Name]; ok {
+			continue
+		}
+		if p.Optional || p.Default != nil {
+			d.addMemberChange(old, new, nil, &new.Properties[i], false, "was added")
+		} else {
+			d.addMemberChange(old, new, nil, &new.Properties[i], usage&sentToServer != 0, "was added as a required property")
+		}
+	}
+}
+
+func (d *differ) compareProperty(oldObject, newObject Object, old, new *Property, usage usage) {
+	d.compareDeprecation(oldObject, newObject, old, new)
+	d.compareTypes(oldObject, newObject, old, new, old.Type, new.Type)
+
+	if old.Optional && !new.Optional {
+		d.addMemberChange(oldObject, newObject, old, new, usage&sentToServer != 0 && new.Default == nil, "is no longer optional")
+	} else if !old.Optional && new.Optional {
+		d.addMemberChange(oldObject, newObject, old, new, usage&sentToClients != 0, "is now optional")
+	}
+
+	switch {
+	case old.Default != nil && new.Default == nil && !new.Optional:
+		d.addMemberChange(oldObject, newObject, old, new, usage&sentToServer != 0, "no longer has a default value")
+	case old.Default == nil && new.Default != nil:
+		d.addMemberChange(oldObject, newObject, old, new, false, "now has the default value %s", new.Default)
+	case old.Default != nil && new.Default != nil && old.Default.String() != new.Default.String():
+		d.addMemberChange(oldObject, newObject, old, new, false, "changed its default value from %s to %s", old.Default, new.Default)
+	}
+
+	for _, c := range new.Constraints {
+		oldConstraint := old.Constraint(c.Name.Lexeme)
+		if oldConstraint == nil {
+			d.addMemberChange(oldObject, newObject, old, new, usage&sentToServer != 0, "has the new constraint %s", c)
+		} else if oldConstraint.Value.String() != c.Value.String() {
+			d.addMemberChange(oldObject, newObject, old, new, usage&sentToServer != 0 && isTighter(*oldConstraint, c), "changed the constraint %s to %s", oldConstraint, c)
+		}
+	}
+	for _, c := range old.Constraints {
+		if new.Constraint(c.Name.Lexeme) == nil {
+			d.addMemberChange(oldObject, newObject, old, new, false, "no longer has the constraint %s", c)
+		}
+	}
+}
+
+// compareTypes compares the type of a property or the value type of an alias or constant.
+// Changing a type to another type with the same underlying type (e.g. an alias of it) is compatible.
+func (d *differ) compareTypes(oldObject, newObject Object, oldProperty, newProperty *Property, old, new *PropertyType) {
+	oldName := typeName(old, oldObject.TypeParameters, nil)
+	newName := typeName(new, newObject.TypeParameters, nil)
+	if oldName == newName {
+		return
+	}
+	breaking := typeName(old, oldObject.TypeParameters, d.oldObjects) != typeName(new, newObject.TypeParameters, d.newObjects)
+	message := "changed its type from '%s' to '%s'"
+	if oldProperty == nil {
+		d.addObjectChange(oldObject, newObject, breaking, message, oldName, newName)
+	} else {
+		d.addMemberChange(oldObject, newObject, oldProperty, newProperty, breaking, message, oldName, newName)
+	}
+}
+
+func (d *differ) compareEnums(old, new Object) {
+	oldType, newType := "string", "string"
+	if old.ValueType != nil {
+		oldType = strings.ToLower(string(old.ValueType.Token.Type))
+	}
+	if new.ValueType != nil {
+		newType = strings.ToLower(string(new.ValueType.Token.Type))
+	}
+	if oldType != newType {
+		d.addObjectChange(old, new, true, "changed its value type from '%s' to '%s'", oldType, newType)
+	}
+
+	oldValues, newValues := old.EnumValues(), new.EnumValues()
+	newIndices := make(map[string]int, len(new.Properties))
+	for i, p := range new.Properties {
+		newIndices[p.Name] = i
+	}
+	for i, p := range old.Properties {
+		j, ok := newIndices[p.Name]
+		if !ok {
+			d.addMemberChange(old, new, &old.Properties[i], nil, true, "was removed")
+			continue
+		}
+		d.compareDeprecation(old, new, &old.Properties[i], &new.Properties[j])
+		if oldType == newType && oldValues[i] != newValues[j] {
+			d.addMemberChange(old, new, &old.Properties[i], &new.Properties[j], true, "changed its value from %s to %s", enumValueString(old, oldValues[i]), enumValueString(new, newValues[j]))
+		}
+	}
+
+	// The generated decoders of clients reject unknown enum values.
+	usage := d.usage(new)
+	oldProperties := propertiesByName(old.Properties)
+	for i, p := range new.Properties {
+		if _, ok := oldProperties[p.Name]; !ok {
+			d.addMemberChange(old, new, nil, &new.Properties[i], usage&sentToClients != 0, "was added")
+		}
+	}
+}
+
+func (d *differ) compareUnionMembers(old, new Object) {
+	oldMembers := propertiesByName(old.Properties)
+	newMembers := propertiesByName(new.Properties)
+	for i, m := range old.Properties {
+		if _, ok := newMembers[m.Name]; !ok {
+			d.addMemberChange(old, new, &old.Properties[i], nil, true, "was removed")
+		}
+	}
+	// The generated decoders of clients reject unknown union members.
+	usage := d.usage(new)
+	for i, m := range new.Properties {
+		if _, ok := oldMembers[m.Name]; !ok {
+			d.addMemberChange(old, new, nil, &new.Properties[i], usage&sentToClients != 0, "was added")
+		}
+	}
+}
+
+// compareDeprecation reports objects, properties and enum values which were deprecated or are no longer deprecated.
+// If old and new are nil, the objects are compared.
+func (d *differ) compareDeprecation(oldObject, newObject Object, old, new *Property) {
+	oldDeprecation, newDeprecation := oldObject.Deprecated, newObject.Deprecated
+	if old != nil {
+		oldDeprecation, newDeprecation = old.Deprecated, new.Deprecated
+	}
+	if (oldDeprecation == nil) == (newDeprecation == nil) {
+		return
+	}
+	message := "is no longer deprecated"
+	if newDeprecation != nil {
+		message = "was deprecated"
+	}
+	if old == nil {
+		d.addObjectChange(oldObject, newObject, false, message)
+	} else {
+		d.addMemberChange(oldObject, newObject, old, new, false, message)
+	}
+}
+
+// usage returns the directions in which the object is sent in either version of the file.
+func (d *differ) usage(o Object) usage {
+	switch o.Type {
+	case EVENT:
+		return sentToClients
+	case COMMAND:
+		return sentToServer
+	case CONFIG:
+		return sentToClients | sentToServer
+	}
+	return d.oldUsage[o.Name.Lexeme] | d.newUsage[o.Name.Lexeme]
+}
+
+func (d *differ) add(change Change) {
+	d.changes = append(d.changes, change)
+}
+
+// addObjectChange adds a change of an object. message describes the change and is preceded by the description of the object.
+func (d *differ) addObjectChange(old, new Object, breaking bool, message string, args ...any) {
+	d.add(Change{
+		Breaking: breaking,
+		Kind:     new.Kind(),
+		Object:   objectName(new),
+		Message:  fmt.Sprintf("%s %s.", capitalize(describe(new)), fmt.Sprintf(message, args...)),
+		Old:      old.Range,
+		New:      new.Range,
+	})
+}
+
+// addMemberChange adds a change of a property, enum value or union member. old or new are nil if the member was added or removed.
+func (d *differ) addMemberChange(oldObject, newObject Object, old, new *Property, breaking bool, message string, args ...any) {
+	change := Change{
+		Breaking: breaking,
+		Kind:     newObject.Kind(),
+		Object:   objectName(newObject),
+	}
+	if old != nil {
+		change.Member = old.Name
+		change.Old = old.Range
+	}
+	if new != nil {
+		change.Member = new.Name
+		change.New = new.Range
+	}
+
+	member := "Property"
+	switch newObject.Type {
+	case ENUM:
+		member = "Enum value"
+	case UNION:
+		member = "Union member"
+	}
+	change.Message = fmt.Sprintf("%s '%s' of %s %s.", member, change.Member, describe(newObject), fmt.Sprintf(message, args...))
+	d.add(change)
+}
+
+// typeUsage returns the directions in which values of every type, enum, union and alias are sent.
+func typeUsage(objects []Object) map[string]usage {
+	types := make(map[string]Object)
+	for _, o := range objects {
+		if objectKey(o) == "type "+o.Name.Lexeme {
+			types[o.Name.Lexeme] = o
+		}
+	}
+
+	result := make(map[string]usage)
+	var mark func(name string, u usage)
+	var markType func(t *PropertyType, u usage)
+	markType = func(t *PropertyType, u usage) {
+		if t == nil {
+			return
+		}
+		if t.Token.Type == IDENTIFIER {
+			mark(t.Token.Lexeme, u)
+		}
+		markType(t.Key, u)
+		markType(t.Generic, u)
+		for _, a := range t.TypeArguments {
+			markType(a, u)
+		}
+	}
+	markObject := func(o Object, u usage) {
+		markType(o.Extends, u)
+		markType(o.ValueType, u)
+		for _, p := range o.Properties {
+			markType(p.Type, u)
+		}
+	}
+	mark = func(name string, u usage) {
+		o, ok := types[name]
+		if !ok || result[name]&u == u {
+			return
+		}
+		result[name] |= u
+		markObject(o, u)
+	}
+
+	for _, o := range objects {
+		switch o.Type {
+		case EVENT:
+			markObject(o, sentToClients)
+		case COMMAND:
+			markObject(o, sentToServer)
+		case CONFIG:
+			markObject(o, sentToClients|sentToServer)
+		}
+	}
+	return result
+}
+
+// typeName returns the name of a type in CGE syntax with canonical names of the primitive types.
+// Type parameters of typeParameters are replaced by their position, so that renaming a type parameter doesn't change the name.
+// If objects is not nil, aliases are replaced by their underlying type.
+func typeName(t *PropertyType, typeParameters []Token, objects map[string]Object) string {
+	if t == nil {
+		return ""
+	}
+	switch t.Token.Type {
+	case IDENTIFIER:
+		if alias, ok := objects["type "+t.Token.Lexeme]; ok && alias.Type == ALIAS {
+			return typeName(alias.ValueType, nil, objects)
+		}
+		if len(t.TypeArguments) == 0 {
+			return t.Token.Lexeme
+		}
+		arguments := make([]string, len(t.TypeArguments))
+		for i, a := range t.TypeArguments {
+			arguments[i] = typeName(a, typeParameters, objects)
+		}
+		return fmt.Sprintf("%s<%s>", t.Token.Lexeme, strings.Join(arguments, ", "))
+	case TYPE_PARAMETER:
+		for i, p := range typeParameters {
+			if p.Lexeme == t.Token.Lexeme {
+				return fmt.Sprintf("$%d", i+1)
+			}
+		}
+		return t.Token.Lexeme
+	case MAP:
+		if t.Key != nil {
+			return fmt.Sprintf("map<%s, %s>", typeName(t.Key, typeParameters, objects), typeName(t.Generic, typeParameters, objects))
+		}
+		return fmt.Sprintf("map<%s>", typeName(t.Generic, typeParameters, objects))
+	case LIST:
+		return fmt.Sprintf("list<%s>", typeName(t.Generic, typeParameters, objects))
+	default:
+		return strings.ToLower(string(t.Token.Type))
+	}
+}
+
+// isTighter returns true if the new value of a constraint allows less values than the old value.
+func isTighter(old, new Constraint) bool {
+	if new.Name.Lexeme == "pattern" {
+		return true
+	}
+	oldValue, err1 := strconv.ParseFloat(old.Value.Value, 64)
+	newValue, err2 := strconv.ParseFloat(new.Value.Value, 64)
+	if err1 != nil || err2 != nil {
+		return true
+	}
+	if strings.HasPrefix(new.Name.Lexeme, "min") {
+		return newValue > oldValue
+	}
+	return newValue < oldValue
+}
+
+// objectKey returns a key which is unique for every object in a file. Commands, events, types and constants have separate namespaces.
+func objectKey(o Object) string {
+	switch o.Type {
+	case CONFIG:
+		return "config"
+	case COMMAND, EVENT, CONST:
+		return fmt.Sprintf("%s %s", o.Kind(), o.Name.Lexeme)
+	default:
+		return "type " + o.Name.Lexeme
+	}
+}
+
+func objectsByKey(objects []Object) map[string]Object {
+	result := make(map[string]Object, len(objects))
+	for _, o := range objects {
+		result[objectKey(o)] = o
+	}
+	return result
+}
+
+func propertiesByName(properties []Property) map[string]*Property {
+	result := make(map[string]*Property, len(properties))
+	for i, p := range properties {
+		result[p.Name] = &properties[i]
+	}
+	return result
+}
+
+func objectName(o Object) string {
+	if o.Type == CONFIG {
+		return ""
+	}
+	return o.Name.Lexeme
+}
+
+// describe returns the kind and name of an object, e.g. "event 'game_over'".
+func describe(o Object) string {
+	switch o.Type {
+	case CONFIG:
+		return "the config"
+	case CONST:
+		return fmt.Sprintf("constant '%s'", o.Name.Lexeme)
+	default:
+		return fmt.Sprintf("%s '%s'", o.Kind(), o.Name.Lexeme)
+	}
+}
+
+func article(kind ObjectType) string {
+	switch kind {
+	case AliasObject, EnumObject, EventObject:
+		return "an " + string(kind)
+	case ConstObject:
+		return "a constant"
+	default:
+		return "a " + string(kind)
+	}
+}
+
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+func scopeName(scope Token) string {
+	if scope.Lexeme == "" {
+		return "none"
+	}
+	return scope.Lexeme
+}
+
+func enumValueString(enum Object, value string) string {
+	if enum.IsIntEnum() {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func containsToken(tokens []Token, lexeme string) bool {
+	for _, t := range tokens {
+		if t.Lexeme == lexeme {
+			return true
+		}
+	}
+	return false
+}
+
+type jsonRange struct {
+	File  string       `json:"file,omitempty"`
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+type jsonChange struct {
+	Breaking bool       `json:"breaking"`
+	Kind     ObjectType `json:"kind,omitempty"`
+	Object   string     `json:"object,omitempty"`
+	Member   string     `json:"member,omitempty"`
+	Message  string     `json:"message"`
+	Old      *jsonRange `json:"old,omitempty"`
+	New      *jsonRange `json:"new,omitempty"`
+}
+
+// RenderChangesJSON encodes the changes as a JSON array. Lines and columns are one-based like in RenderJSON.
+func RenderChangesJSON(changes []Change) ([]byte, error) {
+	result := make([]jsonChange, len(changes))
+	for i, c := range changes {
+		result[i] = jsonChange{
+			Breaking: c.Breaking,
+			Kind:     c.Kind,
+			Object:   c.Object,
+			Member:   c.Member,
+			Message:  c.Message,
+			Old:      jsonRangeOf(c.Old),
+			New:      jsonRangeOf(c.New),
+		}
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+func jsonRangeOf(r Range) *jsonRange {
+	if r == (Range{}) {
+		return nil
+	}
+	return &jsonRange{
+		File:  r.Start.File,
+		Start: jsonPositionOf(r.Start),
+		End:   jsonPositionOf(r.End),
+	}
+}
@@ -0,0 +1,122 @@
+package cge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffAddedEnumValuesAndUnionMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		breaking bool
+	}{
+		{"enum value sent to clients", "event e { d: dir }\nenum dir { up }", "event e { d: dir }\nenum dir { up, down }", true},
+		{"enum value sent to the server", "command c { d: dir }\nenum dir { up }", "command c { d: dir }\nenum dir { up, down }", false},
+		{"enum value in the config", "config { d: dir }\nenum dir { up }", "config { d: dir }\nenum dir { up, down }", true},
+		{"union member sent to clients", "event e { s: shape }\nunion shape(kind) { a }\ntype a {}\ntype b {}", "event e { s: shape }\nunion shape(kind) { a, b }\ntype a {}\ntype b {}", true},
+		{"union member sent to the server", "command c { s: shape }\nunion shape(kind) { a }\ntype a {}\ntype b {}", "command c { s: shape }\nunion shape(kind) { a, b }\ntype a {}\ntype b {}", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes := Diff(parseTestFile(t, test.old), parseTestFile(t, test.new))
+			if len(changes) != 1 {
+				t.Fatalf("expected 1 change, got %d: %v", len(changes), changes)
+			}
+			if changes[0].Breaking != test.breaking {
+				t.Errorf("expected breaking to be %t: %s", test.breaking, changes[0].Message)
+			}
+		})
+	}
+}
+
+func TestDiffRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		breaking bool
+		// message is empty if no change is expected.
+		message string
+	}{
+		// properties
+		{"required property added to an event", "event e { a: int32 }", "event e { a: int32, b: int32 }", false, "Property 'b' of event 'e' was added as a required property."},
+		{"required property added to a command", "command c { a: int32 }", "command c { a: int32, b: int32 }", true, "Property 'b' of command 'c' was added as a required property."},
+		{"optional property added to a command", "command c { a: int32 }", "command c { a: int32, b?: int32 }", false, "Property 'b' of command 'c' was added."},
+		{"property with a default value added to a command", "command c { a: int32 }", "command c { a: int32, b: int32 = 1 }", false, "Property 'b' of command 'c' was added."},
+		{"property removed", "event e { a: int32, b: int32 }", "event e { a: int32 }", true, "Property 'b' of event 'e' was removed."},
+		{"property retyped", "event e { a: int32 }", "event e { a: string }", true, "Property 'a' of event 'e' changed its type from 'int32' to 'string'."},
+		{"property retyped to an alias", "event e { a: int32 }\ntype id = int32", "event e { a: id }\ntype id = int32", false, "Property 'a' of event 'e' changed its type from 'int32' to 'id'."},
+
+		// required and optional
+		{"event property made optional", "event e { a: int32 }", "event e { a?: int32 }", true, "Property 'a' of event 'e' is now optional."},
+		{"command property made optional", "command c { a: int32 }", "command c { a?: int32 }", false, "Property 'a' of command 'c' is now optional."},
+		{"event property made required", "event e { a?: int32 }", "event e { a: int32 }", false, "Property 'a' of event 'e' is no longer optional."},
+		{"command property made required", "command c { a?: int32 }", "command c { a: int32 }", true, "Property 'a' of command 'c' is no longer optional."},
+		{"config property made required", "config { a?: int32 }", "config { a: int32 }", true, "Property 'a' of the config is no longer optional."},
+
+		// constraints
+		{"command constraint tightened", "command c { a: int32 [max=10] }", "command c { a: int32 [max=5] }", true, "Property 'a' of command 'c' changed the constraint max=10 to max=5."},
+		{"command constraint loosened", "command c { a: int32 [max=5] }", "command c { a: int32 [max=10] }", false, "Property 'a' of command 'c' changed the constraint max=5 to max=10."},
+		{"command minimum tightened", "command c { a: int32 [min=1] }", "command c { a: int32 [min=2] }", true, "Property 'a' of command 'c' changed the constraint min=1 to min=2."},
+		{"command pattern changed", "command c { a: string [pattern=\"a\"] }", "command c { a: string [pattern=\"b\"] }", true, "Property 'a' of command 'c' changed the constraint pattern=\"a\" to pattern=\"b\"."},
+		{"event constraint tightened", "event e { a: int32 [max=10] }", "event e { a: int32 [max=5] }", false, "Property 'a' of event 'e' changed the constraint max=10 to max=5."},
+		{"command constraint added", "command c { a: int32 }", "command c { a: int32 [max=5] }", true, "Property 'a' of command 'c' has the new constraint max=5."},
+		{"event constraint added", "event e { a: int32 }", "event e { a: int32 [max=5] }", false, "Property 'a' of event 'e' has the new constraint max=5."},
+		{"command constraint removed", "command c { a: int32 [max=5] }", "command c { a: int32 }", false, "Property 'a' of command 'c' no longer has the constraint max=5."},
+
+		// default values
+		{"default value added", "command c { a: int32 }", "command c { a: int32 = 1 }", false, "Property 'a' of command 'c' now has the default value 1."},
+		{"default value changed", "command c { a: int32 = 1 }", "command c { a: int32 = 2 }", false, "Property 'a' of command 'c' changed its default value from 1 to 2."},
+		{"default value removed", "command c { a: int32 = 1 }", "command c { a: int32 }", true, "Property 'a' of command 'c' no longer has a default value."},
+
+		// declarations
+		{"event removed", "event e { a: int32 }\nevent f { b: int32 }", "event e { a: int32 }", true, "Event 'f' was removed."},
+		{"type removed", "event e { a: int32 }\ntype t { b: int32 }", "event e { a: int32 }", true, "Type 't' was removed."},
+		{"type renamed", "type t { b: int32 }", "type u { b: int32 }", true, "Type 't' was renamed to 'u'."},
+		{"event added", "event e { a: int32 }", "event e { a: int32 }\nevent f { b: int32 }", false, "Event 'f' was added."},
+		{"kind changed", "event e { a: int32 }\ntype t { b: int32 }", "event e { a: int32 }\nenum t { b }", true, "'t' changed from a type to an enum."},
+
+		// extends and generics
+		{"extends added", "type a { x: int32 }\ntype b { y: int32 }", "type a { x: int32 }\ntype b extends a { y: int32 }", true, "Type 'b' now extends 'a'."},
+		{"extends removed", "type a { x: int32 }\ntype b extends a { y: int32 }", "type a { x: int32 }\ntype b { y: int32 }", true, "Type 'b' no longer extends 'a'."},
+		{"extends changed", "type a { x: int32 }\ntype c { x: int32 }\ntype b extends a { y: int32 }", "type a { x: int32 }\ntype c { x: int32 }\ntype b extends c { y: int32 }", true, "Type 'b' now extends 'c' instead of 'a'."},
+		{"type parameter added", "type box<t> { v: t }", "type box<t, u> { v: t }", true, "Type 'box' changed the number of its type parameters from 1 to 2."},
+		{"type parameter renamed", "type box<t> { v: t }", "type box<u> { v: u }", false, ""},
+		{"type argument changed", "event e { b: box<int32> }\ntype box<t> { v: t }", "event e { b: box<string> }\ntype box<t> { v: t }", true, "Property 'b' of event 'e' changed its type from 'box<int32>' to 'box<string>'."},
+
+		// command responses
+		{"response added", "command c -> a {}\nevent a {}\nevent b {}", "command c -> a, b {}\nevent a {}\nevent b {}", false, "Command 'c' now responds with 'b'."},
+		{"response removed", "command c -> a, b {}\nevent a {}\nevent b {}", "command c -> a {}\nevent a {}\nevent b {}", true, "Command 'c' no longer responds with 'b'."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes := Diff(parseTestFile(t, test.old), parseTestFile(t, test.new))
+			if test.message == "" {
+				if len(changes) != 0 {
+					t.Fatalf("expected no changes, got %v", changes)
+				}
+				return
+			}
+			if len(changes) != 1 {
+				t.Fatalf("expected 1 change, got %d: %v", len(changes), changes)
+			}
+			if changes[0].Breaking != test.breaking {
+				t.Errorf("expected breaking to be %t: %s", test.breaking, changes[0].Message)
+			}
+			if changes[0].Message != test.message {
+				t.Errorf("expected message:\n%s\ngot:\n%s", test.message, changes[0].Message)
+			}
+		})
+	}
+}
+
+func parseTestFile(t *testing.T, declarations string) *File {
+	t.Helper()
+	file, errs := ParseAST(strings.NewReader("name test\nversion 0.9\n"+declarations), "test.cge", "dev")
+	if len(errs) > 0 {
+		t.Fatalf("failed to parse:\n%s\n%v", declarations, errs)
+	}
+	return file
+}
@@ -0,0 +1,113 @@
+package cge
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// IsIntEnum returns true if the object is an enum with integer values.
+func (o Object) IsIntEnum() bool {
+	return o.Type == ENUM && o.ValueType != nil && (o.ValueType.Token.Type == INT32 || o.ValueType.Token.Type == INT64)
+}
+
+// EnumValues returns the values of all enum values of the object in the order they were declared.
+// Enum values without an explicit value have their name as their value or,
+// in case of an integer enum, the value of the previous enum value plus one (starting at 0).
+func (o Object) EnumValues() []string {
+	values := make([]string, len(o.Properties))
+	next := int64(0)
+	for i, p := range o.Properties {
+		if o.IsIntEnum() {
+			if p.Value != nil {
+				next, _ = strconv.ParseInt(p.Value.Value, 10, 64)
+			}
+			values[i] = strconv.FormatInt(next, 10)
+			next++
+		} else if p.Value != nil {
+			values[i] = p.Value.Value
+		} else {
+			values[i] = p.Name
+		}
+	}
+	return values
+}
+
+func (p *parser) enumType() (*PropertyType, error) {
+	if !p.match(COLON) {
+		return nil, nil
+	}
+
+	if !p.match(STRING, INT32, INT64) {
+		return nil, p.newError(CodeSyntax, "Expect 'string', 'int32' or 'int64' after ':'.", false)
+	}
+
+	return &PropertyType{
+		Token: p.previous(),
+		Range: p.previous().Range(),
+	}, nil
+}
+
+// checkEnumValues makes sure that no name is used twice, that all explicit values match the enum type and that no value is used twice.
+func (p *parser) checkEnumValues(valueType *PropertyType, properties []Property, names []Token) {
+	// Enum values with a duplicate name are not checked for duplicate values to avoid reporting the same mistake twice.
+	duplicateNames := make(map[int]struct{})
+	nameSet := make(map[string]struct{}, len(names))
+	for i, name := range names {
+		if _, ok := nameSet[name.Lexeme]; ok {
+			p.errors = append(p.errors, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Duplicate enum value name '%s'.", name.Lexeme), name, true))
+			duplicateNames[i] = struct{}{}
+			continue
+		}
+		nameSet[name.Lexeme] = struct{}{}
+	}
+
+	if valueType == nil {
+		valueType = &PropertyType{
+			Token: Token{
+				Type:   STRING,
+				Lexeme: "string",
+			},
+		}
+	}
+
+	valid := true
+	for _, property := range properties {
+		if property.Value == nil {
+			continue
+		}
+		if property.Value.Token.Type == IDENTIFIER {
+			p.errors = append(p.errors, p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Invalid value for type '%s'.", valueType.Token.Lexeme), property.Value.Token, true))
+			valid = false
+			continue
+		}
+		err := p.checkLiteral(property.Value, valueType)
+		if err != nil {
+			p.errors = append(p.errors, err)
+			valid = false
+		}
+	}
+	if !valid {
+		return
+	}
+
+	enum := Object{
+		Type:       ENUM,
+		Properties: properties,
+		ValueType:  valueType,
+	}
+	values := make(map[string]struct{}, len(properties))
+	for i, value := range enum.EnumValues() {
+		if _, ok := duplicateNames[i]; ok {
+			continue
+		}
+		if _, ok := values[value]; ok {
+			token := names[i]
+			if properties[i].Value != nil {
+				token = properties[i].Value.Token
+			}
+			p.errors = append(p.errors, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Duplicate enum value '%s'.", value), token, true))
+			continue
+		}
+		values[value] = struct{}{}
+	}
+}
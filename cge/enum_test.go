@@ -0,0 +1,66 @@
+package cge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnumDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		enum     string
+		expected []string
+	}{
+		{
+			name:     "unique",
+			enum:     `enum x { a, b = "c", c_ = "a_" }`,
+			expected: nil,
+		},
+		{
+			name:     "duplicate string enum name",
+			enum:     `enum x { a, b, a }`,
+			expected: []string{"Duplicate enum value name 'a'."},
+		},
+		{
+			name:     "duplicate string enum name with different values",
+			enum:     `enum x { a = "b", a = "c" }`,
+			expected: []string{"Duplicate enum value name 'a'."},
+		},
+		{
+			name:     "duplicate string enum value",
+			enum:     `enum x { a, b = "a" }`,
+			expected: []string{"Duplicate enum value 'a'."},
+		},
+		{
+			name:     "duplicate int enum name",
+			enum:     `enum x: int32 { a, a }`,
+			expected: []string{"Duplicate enum value name 'a'."},
+		},
+		{
+			name:     "duplicate int enum name with explicit values",
+			enum:     `enum x: int64 { a = 1, b = 2, a = 3 }`,
+			expected: []string{"Duplicate enum value name 'a'."},
+		},
+		{
+			name:     "duplicate int enum value",
+			enum:     `enum x: int32 { a = 1, b = 0, c }`,
+			expected: []string{"Duplicate enum value '1'."},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, errs := Parse(strings.NewReader("name test\nversion 0.4\n"+test.enum), "dev")
+			messages := make([]string, len(errs))
+			for i, err := range errs {
+				parseErr, ok := err.(ParseError)
+				if !ok {
+					t.Fatalf("expected ParseError, got %T: %s", err, err)
+				}
+				messages[i] = parseErr.Message
+			}
+			if strings.Join(messages, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("expected errors:\n%s\ngot:\n%s", strings.Join(test.expected, "\n"), strings.Join(messages, "\n"))
+			}
+		})
+	}
+}
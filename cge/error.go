@@ -5,23 +5,45 @@ import (
 	"strings"
 )
 
-func generateErrorText(message string, lineText []rune, line, columnStart, columnEnd int) string {
+func formatPosition(position Position) string {
+	if position.File != "" {
+		return fmt.Sprintf("[%s:%d:%d]", position.File, position.Line+1, position.Column+1)
+	}
+	return fmt.Sprintf("[%d:%d]", position.Line+1, position.Column+1)
+}
+
+func generateErrorText(message, file string, lineText []rune, line, columnStart, columnEnd int, color bool) string {
 	if columnEnd >= len(lineText) {
 		lineText = append(lineText, []rune(strings.Repeat(" ", columnEnd-(len(lineText)-1)))...)
 	}
 
+	position := formatPosition(Position{File: file, Line: line, Column: columnStart})
+
 	length := len(lineText)
 	lineText = []rune(strings.TrimPrefix(strings.TrimPrefix(string(lineText), " "), "\t"))
 	columnStart = columnStart - (length - len(lineText))
 	columnEnd = columnEnd - (length - len(lineText))
 
-	errorLine := string(lineText[:columnStart])
-	errorLine = errorLine + "\x1b[4m\x1b[31m"
-	errorLine = errorLine + string(lineText[columnStart:columnEnd])
-	errorLine = errorLine + "\x1b[0m"
-	errorLine = errorLine + string(lineText[columnEnd:])
+	lineNumber := fmt.Sprintf("[%d]  ", line+1)
+	var text string
+	if color {
+		errorLine := string(lineText[:columnStart])
+		errorLine = errorLine + "\x1b[4m\x1b[31m"
+		errorLine = errorLine + string(lineText[columnStart:columnEnd])
+		errorLine = errorLine + "\x1b[0m"
+		errorLine = errorLine + string(lineText[columnEnd:])
+		text = fmt.Sprintf("\x1b[2m%s\x1b[0m%s", lineNumber, errorLine)
+	} else {
+		indentation := []rune(string(lineText[:columnStart]))
+		for i, c := range indentation {
+			if c != '\t' {
+				indentation[i] = ' '
+			}
+		}
+		marker := strings.Repeat(" ", len(lineNumber)) + string(indentation) + strings.Repeat("^", columnEnd-columnStart)
+		text = fmt.Sprintf("%s%s\n%s", lineNumber, strings.TrimRight(string(lineText), " "), marker)
+	}
 
-	text := fmt.Sprintf("\x1b[2m[%d]  \x1b[0m%s", line+1, errorLine)
-	text = fmt.Sprintf("%s%s\n%s\n%s", fmt.Sprintf("[%d:%d] %s\n", line+1, columnStart+1, message), strings.Repeat("-", 30), text, strings.Repeat("-", 30))
+	text = fmt.Sprintf("%s%s\n%s\n%s", fmt.Sprintf("%s %s\n", position, message), strings.Repeat("-", 30), text, strings.Repeat("-", 30))
 	return text
 }
@@ -0,0 +1,76 @@
+package cge
+
+import "fmt"
+
+// InheritedProperties returns all properties object inherits from its base types, starting with the properties of the outermost base type.
+func InheritedProperties(objects []Object, object Object) []Property {
+	types := make(map[string]Object)
+	for _, o := range objects {
+		if o.Type == TYPE {
+			types[o.Name.Lexeme] = o
+		}
+	}
+
+	chain := make([]Object, 0)
+	visited := make(map[string]struct{})
+	if object.Type == TYPE {
+		visited[object.Name.Lexeme] = struct{}{}
+	}
+	for object.Extends != nil {
+		base, ok := types[object.Extends.Token.Lexeme]
+		if !ok {
+			break
+		}
+		if _, ok := visited[base.Name.Lexeme]; ok {
+			break
+		}
+		visited[base.Name.Lexeme] = struct{}{}
+		chain = append(chain, base)
+		object = base
+	}
+
+	properties := make([]Property, 0)
+	for i := len(chain) - 1; i >= 0; i-- {
+		properties = append(properties, chain[i].Properties...)
+	}
+	return properties
+}
+
+// checkExtends makes sure that all base types are types and that no property is declared in both an object and one of its base types.
+func (p *parser) checkExtends() {
+	objects := make(map[string]Object, len(p.objects))
+	for _, o := range p.objects {
+		if o.Type != EVENT && o.Type != COMMAND {
+			objects[o.Name.Lexeme] = o
+		}
+	}
+
+	for _, o := range p.objects {
+		if o.Extends == nil {
+			continue
+		}
+
+		base, ok := objects[o.Extends.Token.Lexeme]
+		if !ok {
+			continue
+		}
+		if base.Type != TYPE {
+			p.errors = append(p.errors, p.newErrorAt(CodeInvalidExtends, fmt.Sprintf("'%s' cannot be extended because it is not a type.", base.Name.Lexeme), o.Extends.Token, false))
+			continue
+		}
+		if len(base.TypeParameters) > 0 {
+			p.errors = append(p.errors, p.newErrorAt(CodeInvalidExtends, fmt.Sprintf("'%s' cannot be extended because it is generic.", base.Name.Lexeme), o.Extends.Token, false))
+			continue
+		}
+
+		inherited := make(map[string]struct{})
+		for _, property := range InheritedProperties(p.objects, o) {
+			inherited[property.Name] = struct{}{}
+		}
+		for _, property := range o.Properties {
+			if _, ok := inherited[property.Name]; ok {
+				p.errors = append(p.errors, p.newErrorAt(CodeInvalidExtends, fmt.Sprintf("Property '%s' of '%s' is already defined in a base type.", property.Name, o.Name.Lexeme), o.Name, false))
+			}
+		}
+	}
+}
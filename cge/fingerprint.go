@@ -0,0 +1,92 @@
+package cge
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns a stable hash of the parts of a schema which affect the messages sent between clients and servers:
+// the game name and the names, types, values and constraints of all declarations.
+// Comments, formatting, attributes, deprecations, the CGE version and the order of declarations, properties,
+// enum values and union members don't affect the fingerprint.
+//
+// Clients and servers with the same fingerprint are generated from equivalent schemas.
+// The fingerprint is a hex encoded SHA-256 hash.
+func Fingerprint(metadata Metadata, objects []Object) string {
+	declarations := make([]string, 0, len(objects))
+	for _, o := range objects {
+		declarations = append(declarations, fmt.Sprintf("%s\n%s", objectKey(o), signature(o, nil)))
+	}
+	sort.Strings(declarations)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "name %s\n", metadata.Name)
+	for _, d := range declarations {
+		fmt.Fprintf(hash, "\n%s\n", d)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// signature returns a description of the definition of an object without its name, comments and positions.
+// The order of properties, enum values, union members, constraints and responses doesn't affect the signature.
+// If objects is not nil, aliases are replaced by their underlying type.
+func signature(o Object, objects map[string]Object) string {
+	var b strings.Builder
+	b.WriteString(string(o.Kind()))
+	if o.Extends != nil {
+		fmt.Fprintf(&b, " extends %s", o.Extends.Token.Lexeme)
+	}
+	if o.ValueType != nil {
+		fmt.Fprintf(&b, " %s", typeName(o.ValueType, nil, objects))
+	} else if o.Type == ENUM {
+		b.WriteString(" string")
+	}
+	if o.Value != nil {
+		fmt.Fprintf(&b, " = %s", o.Value)
+	}
+
+	responses := make([]string, len(o.Responses))
+	for i, r := range o.Responses {
+		responses[i] = r.Lexeme
+	}
+	sort.Strings(responses)
+	fmt.Fprintf(&b, " %d %s %s %s", len(o.TypeParameters), o.Scope.Lexeme, o.Discriminator.Lexeme, strings.Join(responses, ","))
+
+	var enumValues []string
+	if o.Type == ENUM {
+		enumValues = o.EnumValues()
+	}
+	properties := make([]string, len(o.Properties))
+	for i, p := range o.Properties {
+		property := p.Name
+		if p.Optional {
+			property += "?"
+		}
+		if p.Type != nil && o.Type != UNION {
+			property += ": " + typeName(p.Type, o.TypeParameters, objects)
+		}
+		constraints := make([]string, len(p.Constraints))
+		for i, c := range p.Constraints {
+			constraints[i] = c.String()
+		}
+		sort.Strings(constraints)
+		if len(constraints) > 0 {
+			property += " [" + strings.Join(constraints, ", ") + "]"
+		}
+		if p.Default != nil {
+			property += " = " + p.Default.String()
+		}
+		if enumValues != nil {
+			property += " = " + enumValues[i]
+		}
+		properties[i] = property
+	}
+	sort.Strings(properties)
+	for _, p := range properties {
+		fmt.Fprintf(&b, "\n%s", p)
+	}
+	return b.String()
+}
@@ -0,0 +1,423 @@
+package cge
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Format returns the canonical formatting of a CGE file:
+//   - tabs are used for indentation
+//   - every property, enum value and union member is on its own line without a trailing comma
+//   - attributes are on their own line in front of the declaration, property or enum value
+//   - top-level declarations are separated by a blank line (imports, constants and aliases may be grouped)
+//   - other blank lines are kept, but multiple blank lines are collapsed into one
+//
+// All comments are preserved at their position relative to the surrounding tokens.
+// Imports are not resolved. Format returns the syntax errors of the file if it cannot be formatted.
+func Format(source io.Reader, filename string) ([]byte, []error) {
+	data, err := io.ReadAll(source)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	tokens, lines, errs, err := scan(bytes.NewReader(data), filename)
+	if err != nil {
+		return nil, []error{err}
+	}
+	errs = append(errs, syntaxErrors(tokens, lines, filename)...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	tokens, _, _, err = scanLossless(bytes.NewReader(data), filename)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	f := &formatter{
+		tokens: tokens,
+	}
+	return f.format(), nil
+}
+
+// syntaxErrors parses a single file without resolving imports and returns all errors which prevent it from being formatted.
+func syntaxErrors(tokens []Token, lines [][]rune, filename string) []error {
+	p := &parser{
+		tokens:                  tokens,
+		lines:                   lines,
+		filename:                filename,
+		sources:                 map[string][][]rune{filename: lines},
+		imports:                 newImportState(filename),
+		commands:                make(map[string]Token),
+		events:                  make(map[string]Token),
+		types:                   make(map[string]Token),
+		constants:               make(map[string]Token),
+		accessedTypeIdentifiers: make([]Token, 0),
+		objects:                 make([]Object, 0),
+		errors:                  make([]error, 0),
+		cgeVersion:              "dev",
+		skipImports:             true,
+	}
+	p.parseImport()
+
+	errs := make([]error, 0)
+	for _, err := range withoutErrorTokens(p.errors) {
+		if e, ok := err.(ParseError); ok && e.Code == CodeSyntax {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+type formatContext int
+
+const (
+	// blockContext is a non-empty block with one property, enum value or union member per line.
+	blockContext formatContext = iota
+	// literalContext is an empty map literal or block ('{}').
+	literalContext
+	squareContext
+	angleContext
+	parenContext
+	// attributeContext contains the arguments of an attribute.
+	attributeContext
+)
+
+type formatter struct {
+	tokens   []Token
+	contexts []formatContext
+	indent   int
+
+	out bytes.Buffer
+	// line is the current output line without indentation.
+	line        strings.Builder
+	lineStarted bool
+	lineIndent  int
+	// trailing contains the line comments which are appended to the current line.
+	trailing []string
+
+	// attributeEnded is true if the last written token ended an attribute.
+	attributeEnded bool
+	// singleLineDeclaration is true if the current top-level declaration is an import, constant or alias.
+	singleLineDeclaration bool
+}
+
+func (f *formatter) format() []byte {
+	prev := -1
+	comments := make([]Token, 0)
+	for i, t := range f.tokens {
+		if t.Type == COMMENT {
+			comments = append(comments, t)
+			continue
+		}
+		if t.Type == COMMA && f.context() == blockContext && f.nextSignificant(i).Type == CLOSE_CURLY {
+			// trailing comma
+			continue
+		}
+
+		comments = f.trailingComments(prev, comments, t)
+
+		nextLine := t.Line
+		if len(comments) > 0 {
+			nextLine = comments[0].Line
+		}
+		gap := prev >= 0 && nextLine-f.tokens[prev].Line > 1
+		newline, blank, space := f.layout(prev, i, gap)
+
+		if len(comments) > 0 {
+			f.newline(newline && blank)
+			for j, c := range comments {
+				if j > 0 {
+					f.newline(c.Line-commentEndLine(comments[j-1]) > 1)
+				}
+				f.writeComment(c, false)
+			}
+			last := comments[len(comments)-1]
+			if strings.HasPrefix(last.Lexeme, "/*") && commentEndLine(last) == t.Line && t.Type != EOF && t.Type != CLOSE_CURLY {
+				newline, blank, space = false, false, true
+			} else {
+				newline, blank = true, t.Line-commentEndLine(last) > 1 && t.Type != EOF && t.Type != CLOSE_CURLY
+			}
+		}
+
+		if t.Type == EOF {
+			f.newline(false)
+			break
+		}
+
+		if t.Type == CLOSE_CURLY && f.context() == blockContext {
+			f.indent--
+		}
+		if newline {
+			f.newline(blank)
+		}
+		f.write(t.Lexeme, space)
+		f.update(i)
+		prev = i
+		comments = comments[:0]
+	}
+	return f.out.Bytes()
+}
+
+// trailingComments writes the comments which start on the line of the previous token and returns the remaining comments.
+// Line comments are appended to the end of the output line, block comments stay in front of the next token.
+func (f *formatter) trailingComments(prev int, comments []Token, next Token) []Token {
+	if prev < 0 {
+		return comments
+	}
+	line := f.tokens[prev].Line
+	for len(comments) > 0 && comments[0].Line == line {
+		c := comments[0]
+		comments = comments[1:]
+		if strings.HasPrefix(c.Lexeme, "//") {
+			f.trailing = append(f.trailing, c.Lexeme)
+			continue
+		}
+		following := next
+		if len(comments) > 0 {
+			following = comments[0]
+		}
+		if following.Line == commentEndLine(c) && f.context() == blockContext && (f.tokens[prev].Type == COMMA || f.tokens[prev].Type == OPEN_CURLY) {
+			// the comment belongs to the next item
+			return append([]Token{c}, comments...)
+		}
+		f.writeComment(c, true)
+		line = commentEndLine(c)
+		if following.Line != line {
+			break
+		}
+	}
+	return comments
+}
+
+// layout returns whether the token at index next starts a new line (optionally after a blank line)
+// or whether it is separated from the previous token by a space.
+// gap is true if there is a blank line in front of the token or its leading comments.
+func (f *formatter) layout(prev, next int, gap bool) (newline, blank, space bool) {
+	if prev < 0 {
+		return false, false, false
+	}
+	p := f.tokens[prev]
+	t := f.tokens[next]
+
+	if t.Type == EOF || f.attributeEnded {
+		return true, false, false
+	}
+
+	switch f.context() {
+	case -1:
+		if t.Type == VERSION {
+			return true, false, false
+		}
+		if p.Type == VERSION_NUMBER {
+			return true, true, false
+		}
+		if f.isDeclarationStart(prev, next) {
+			singleLine := f.isSingleLineDeclaration(next)
+			return true, gap || !singleLine || !f.singleLineDeclaration, false
+		}
+	case blockContext:
+		if p.Type == OPEN_CURLY || t.Type == CLOSE_CURLY {
+			return true, false, false
+		}
+		if p.Type == COMMA {
+			return true, gap, false
+		}
+	}
+
+	return false, false, f.space(p, t)
+}
+
+// space returns whether the tokens p and t on the same line are separated by a space.
+func (f *formatter) space(p, t Token) bool {
+	switch t.Type {
+	case COMMA, COLON, QUESTION, OPEN_PAREN, CLOSE_PAREN, CLOSE_SQUARE, LESS, GREATER:
+		return false
+	case CLOSE_CURLY:
+		return f.context() != literalContext
+	}
+	switch p.Type {
+	case OPEN_PAREN, OPEN_SQUARE, LESS, AT:
+		return false
+	}
+	if f.context() == squareContext && (p.Type == EQUAL || t.Type == EQUAL) {
+		return false
+	}
+	return true
+}
+
+// isDeclarationStart reports whether the top-level token at index next is the first token of a declaration.
+func (f *formatter) isDeclarationStart(prev, next int) bool {
+	switch f.tokens[next].Type {
+	case IMPORT, CONST, CONFIG, COMMAND, EVENT, UNION, AT:
+		return true
+	case TYPE, ENUM:
+		// inline types in aliases
+		return f.tokens[prev].Type != EQUAL && f.tokens[prev].Type != COLON
+	}
+	return false
+}
+
+// isSingleLineDeclaration reports whether the declaration starting at index next is an import, constant or alias.
+func (f *formatter) isSingleLineDeclaration(next int) bool {
+	i := next
+	for f.tokens[i].Type == AT || f.tokens[i].Type == COMMENT {
+		if f.tokens[i].Type == COMMENT {
+			i++
+			continue
+		}
+		i = f.skipAttribute(i)
+	}
+	switch f.tokens[i].Type {
+	case IMPORT, CONST:
+		return true
+	case TYPE:
+		name := f.significant(i + 1)
+		return f.tokens[name].Type == IDENTIFIER && f.tokens[f.significant(name+1)].Type == EQUAL
+	}
+	return false
+}
+
+// skipAttribute returns the index of the first token after the attribute starting at index i.
+func (f *formatter) skipAttribute(i int) int {
+	i = f.significant(i + 1)
+	if f.tokens[i].Type == IDENTIFIER {
+		i = f.significant(i + 1)
+	}
+	if f.tokens[i].Type == OPEN_PAREN {
+		for f.tokens[i].Type != CLOSE_PAREN && f.tokens[i].Type != EOF {
+			i++
+		}
+		if f.tokens[i].Type == CLOSE_PAREN {
+			i++
+		}
+	}
+	return i
+}
+
+// update updates the state of the formatter after the token at index i has been written.
+func (f *formatter) update(i int) {
+	t := f.tokens[i]
+	f.attributeEnded = false
+
+	switch t.Type {
+	case OPEN_CURLY:
+		if f.tokens[i+1].Type == CLOSE_CURLY {
+			f.contexts = append(f.contexts, literalContext)
+		} else {
+			f.contexts = append(f.contexts, blockContext)
+			f.indent++
+		}
+	case OPEN_SQUARE:
+		f.contexts = append(f.contexts, squareContext)
+	case LESS:
+		f.contexts = append(f.contexts, angleContext)
+	case OPEN_PAREN:
+		if i >= 2 && f.tokens[i-1].Type == IDENTIFIER && f.tokens[i-2].Type == AT {
+			f.contexts = append(f.contexts, attributeContext)
+		} else {
+			f.contexts = append(f.contexts, parenContext)
+		}
+	case CLOSE_CURLY, CLOSE_SQUARE, GREATER, CLOSE_PAREN:
+		if len(f.contexts) > 0 {
+			f.attributeEnded = f.context() == attributeContext
+			f.contexts = f.contexts[:len(f.contexts)-1]
+		}
+	case IDENTIFIER:
+		if i >= 1 && f.tokens[i-1].Type == AT && f.tokens[i+1].Type != OPEN_PAREN {
+			f.attributeEnded = true
+		}
+	}
+
+	if len(f.contexts) == 0 && (t.Type == AT || t.Type == IMPORT || t.Type == CONST || t.Type == CONFIG || t.Type == COMMAND || t.Type == EVENT || t.Type == UNION || t.Type == TYPE || t.Type == ENUM) {
+		prev := i - 1
+		for prev >= 0 && f.tokens[prev].Type == COMMENT {
+			prev--
+		}
+		if prev < 0 || f.isDeclarationStart(prev, i) {
+			f.singleLineDeclaration = f.isSingleLineDeclaration(i)
+		}
+	}
+}
+
+// context returns the innermost context or -1 at the top level.
+func (f *formatter) context() formatContext {
+	if len(f.contexts) == 0 {
+		return -1
+	}
+	return f.contexts[len(f.contexts)-1]
+}
+
+// significant returns the index of the first token at or after index i which is not a comment.
+func (f *formatter) significant(i int) int {
+	for f.tokens[i].Type == COMMENT {
+		i++
+	}
+	return i
+}
+
+func (f *formatter) nextSignificant(i int) Token {
+	return f.tokens[f.significant(i+1)]
+}
+
+func (f *formatter) write(text string, space bool) {
+	if !f.lineStarted {
+		f.lineStarted = true
+		f.lineIndent = f.indent
+	} else if space {
+		f.line.WriteString(" ")
+	}
+	f.line.WriteString(text)
+}
+
+// writeComment writes a comment. The lines of a block comment are indented relative to the indentation of the current line.
+func (f *formatter) writeComment(c Token, space bool) {
+	lines := strings.Split(c.Lexeme, "\n")
+	f.write(lines[0], space)
+	for _, line := range lines[1:] {
+		f.flush()
+		f.lineStarted = true
+		f.lineIndent = f.indent
+		runes := []rune(line)
+		strip := 0
+		for strip < len(runes) && strip < c.Column && (runes[strip] == ' ' || runes[strip] == '\t') {
+			strip++
+		}
+		f.line.WriteString(string(runes[strip:]))
+	}
+}
+
+// newline ends the current line if it is not empty. If blank is true, an empty line is inserted unless the output is empty.
+func (f *formatter) newline(blank bool) {
+	if f.lineStarted || len(f.trailing) > 0 {
+		f.flush()
+	}
+	if blank && f.out.Len() > 0 && !bytes.HasSuffix(f.out.Bytes(), []byte("\n\n")) {
+		f.out.WriteString("\n")
+	}
+}
+
+func (f *formatter) flush() {
+	text := f.line.String()
+	if len(f.trailing) > 0 {
+		if text != "" {
+			text += " "
+		}
+		text += strings.Join(f.trailing, " ")
+	}
+	text = strings.TrimRight(text, " \t")
+	if text != "" {
+		f.out.WriteString(strings.Repeat("\t", f.lineIndent))
+		f.out.WriteString(text)
+	}
+	f.out.WriteString("\n")
+
+	f.line.Reset()
+	f.lineStarted = false
+	f.trailing = nil
+}
+
+func commentEndLine(c Token) int {
+	return c.Line + strings.Count(c.Lexeme, "\n")
+}
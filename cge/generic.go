@@ -0,0 +1,121 @@
+package cge
+
+import "fmt"
+
+// typeParameterList parses the type parameters of a generic type declaration ('<' IDENTIFIER (',' IDENTIFIER)* '>').
+// The opening '<' must already be consumed.
+func (p *parser) typeParameterList() ([]Token, error) {
+	parameters := make([]Token, 0)
+	names := make(map[string]struct{})
+	for {
+		if !p.match(IDENTIFIER) {
+			return nil, p.newError(CodeSyntax, "Expect type parameter name.", false)
+		}
+		parameter := p.previous()
+		if _, ok := names[parameter.Lexeme]; ok {
+			return nil, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Duplicate type parameter '%s'.", parameter.Lexeme), parameter, false)
+		}
+		names[parameter.Lexeme] = struct{}{}
+		parameters = append(parameters, parameter)
+
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	if !p.match(GREATER) {
+		return nil, p.newError(CodeSyntax, "Expect '>' after type parameters.", false)
+	}
+
+	return parameters, nil
+}
+
+// typeArgumentList parses the type arguments of an instantiation of a generic type (propertyType (',' propertyType)* '>').
+// The opening '<' must already be consumed.
+func (p *parser) typeArgumentList() ([]*PropertyType, error) {
+	arguments := make([]*PropertyType, 0)
+	for {
+		argument, err := p.propertyType()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	if !p.match(GREATER) {
+		return nil, p.newError(CodeSyntax, "Expect '>' after type arguments.", true)
+	}
+
+	return arguments, nil
+}
+
+// isTypeParameter reports whether name is a type parameter of the generic type which is currently being parsed.
+func (p *parser) isTypeParameter(name string) bool {
+	for _, t := range p.typeParameters {
+		if t.Lexeme == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTypeArguments makes sure that every generic type is instantiated with the correct number of type arguments
+// and that no type arguments are passed to types which are not generic.
+func (p *parser) checkTypeArguments() {
+	objects := make(map[string]Object)
+	for _, o := range p.objects {
+		if o.Type == TYPE || o.Type == ENUM || o.Type == UNION || o.Type == ALIAS {
+			objects[o.Name.Lexeme] = o
+		}
+	}
+
+	for _, t := range p.typeReferences {
+		object, ok := objects[t.Token.Lexeme]
+		if !ok {
+			continue
+		}
+		if len(object.TypeParameters) == 0 {
+			if len(t.TypeArguments) > 0 {
+				p.errors = append(p.errors, p.newErrorAt(CodeTypeArguments, fmt.Sprintf("Type '%s' is not generic.", t.Token.Lexeme), t.Token, true))
+			}
+			continue
+		}
+		if len(t.TypeArguments) != len(object.TypeParameters) {
+			p.errors = append(p.errors, p.newErrorAt(CodeTypeArguments, fmt.Sprintf("Type '%s' expects %d type argument(s) but got %d.", t.Token.Lexeme, len(object.TypeParameters), len(t.TypeArguments)), t.Token, true))
+		}
+	}
+}
+
+// embedsTypeParameter reports whether a value of the type parameter at index of object is contained directly
+// (not in a list or map) in a value of object. visited holds the generic types which are already being checked.
+func embedsTypeParameter(objects map[string]*declCycleObj, object Object, index int, visited map[string]struct{}) bool {
+	if _, ok := visited[object.Name.Lexeme]; ok {
+		return false
+	}
+	visited[object.Name.Lexeme] = struct{}{}
+	defer delete(visited, object.Name.Lexeme)
+
+	parameter := object.TypeParameters[index].Lexeme
+	for _, property := range object.Properties {
+		if property.Type.Token.Type == TYPE_PARAMETER && property.Type.Token.Lexeme == parameter {
+			return true
+		}
+		if property.Type.Token.Type != IDENTIFIER {
+			continue
+		}
+		generic, ok := objects[property.Type.Token.Lexeme]
+		if !ok || len(generic.o.TypeParameters) != len(property.Type.TypeArguments) {
+			continue
+		}
+		for i, argument := range property.Type.TypeArguments {
+			if argument.Token.Type == TYPE_PARAMETER && argument.Token.Lexeme == parameter && embedsTypeParameter(objects, generic.o, i, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package cge
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type importState struct {
+	stack    []string
+	imported map[string]struct{}
+}
+
+func newImportState(filename string) *importState {
+	state := &importState{
+		stack:    make([]string, 0, 1),
+		imported: make(map[string]struct{}),
+	}
+	if filename != "" {
+		state.stack = append(state.stack, filename)
+	}
+	return state
+}
+
+func (p *parser) importDeclaration() error {
+	if !p.match(STRING_LITERAL) {
+		return p.newError(CodeSyntax, "Expect file name after 'import' keyword.", false)
+	}
+	pathToken := p.previous()
+
+	path, err := strconv.Unquote(pathToken.Lexeme)
+	if err != nil || path == "" {
+		return p.newErrorAt(CodeImport, "Invalid file name.", pathToken, false)
+	}
+	if p.skipImports {
+		return nil
+	}
+
+	filename := resolveImportPath(p.filename, path)
+	key := importKey(filename)
+
+	for i, f := range p.imports.stack {
+		if importKey(f) == key {
+			names := make([]string, 0, len(p.imports.stack)-i+1)
+			names = append(names, p.imports.stack[i:]...)
+			names = append(names, filename)
+			return p.newErrorAt(CodeImportCycle, fmt.Sprintf("Import cycle detected: %s", strings.Join(names, " -> ")), pathToken, false)
+		}
+	}
+
+	if _, ok := p.imports.imported[key]; ok {
+		return nil
+	}
+	p.imports.imported[key] = struct{}{}
+
+	file, err := openSource(filename)
+	if err != nil {
+		return p.newErrorAt(CodeImport, fmt.Sprintf("Failed to import '%s': %s", path, err), pathToken, false)
+	}
+	defer file.Close()
+
+	tokens, lines, scanErrs, err := scan(file, filename)
+	if err != nil {
+		return err
+	}
+	p.sources[filename] = lines
+	p.errors = append(p.errors, scanErrs...)
+
+	p.imports.stack = append(p.imports.stack, filename)
+	defer func() {
+		p.imports.stack = p.imports.stack[:len(p.imports.stack)-1]
+	}()
+
+	importParser := &parser{
+		tokens:                  tokens,
+		lines:                   lines,
+		filename:                filename,
+		sources:                 p.sources,
+		imports:                 p.imports,
+		commands:                p.commands,
+		events:                  p.events,
+		types:                   p.types,
+		constants:               p.constants,
+		config:                  p.config,
+		accessedTypeIdentifiers: p.accessedTypeIdentifiers,
+		typeLiterals:            p.typeLiterals,
+		mapKeys:                 p.mapKeys,
+		typeReferences:          p.typeReferences,
+		objects:                 p.objects,
+		errors:                  p.errors,
+		warnings:                p.warnings,
+		cgeVersion:              p.cgeVersion,
+	}
+	importParser.parseImport()
+
+	p.config = importParser.config
+	p.accessedTypeIdentifiers = importParser.accessedTypeIdentifiers
+	p.typeLiterals = importParser.typeLiterals
+	p.mapKeys = importParser.mapKeys
+	p.typeReferences = importParser.typeReferences
+	p.objects = importParser.objects
+	p.errors = importParser.errors
+	p.warnings = importParser.warnings
+
+	return nil
+}
+
+// parseImport parses an imported file. Unlike the main file, imported files don't need to start with metadata.
+func (p *parser) parseImport() {
+	next := p.current
+	for p.tokens[next].Type == COMMENT {
+		next++
+	}
+	if p.tokens[next].Type == NAME {
+		_, _, err := p.name()
+		if err != nil {
+			p.errors = append(p.errors, err)
+			return
+		}
+		version, err := p.version()
+		if err != nil {
+			p.errors = append(p.errors, err)
+			return
+		}
+		if !isVersionCompatible(version.Lexeme, p.cgeVersion) {
+			p.warnAt(CodeVersionMismatch, fmt.Sprintf("CGE version mismatch! Imported file '%s': v%s, cg-gen-events: v%s. There might be parsing issues.", p.filename, version.Lexeme, p.cgeVersion), version)
+		}
+	}
+
+	p.declarations()
+}
+
+func resolveImportPath(importer, path string) string {
+	if isURL(path) {
+		return path
+	}
+
+	if isURL(importer) {
+		base, err := url.Parse(importer)
+		if err != nil {
+			return path
+		}
+		ref, err := url.Parse(path)
+		if err != nil {
+			return path
+		}
+		return base.ResolveReference(ref).String()
+	}
+
+	if filepath.IsAbs(path) || importer == "" {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(filepath.Dir(importer), path)
+}
+
+func importKey(filename string) string {
+	if isURL(filename) {
+		return filename
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.Clean(filename)
+	}
+	return abs
+}
+
+func openSource(filename string) (io.ReadCloser, error) {
+	if !isURL(filename) {
+		return os.Open(filename)
+	}
+
+	resp, err := http.Get(filename)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/plain") {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unsupported content type: expected %s, got %s", "text/plain", resp.Header.Get("Content-Type"))
+	}
+	return resp.Body, nil
+}
+
+func isURL(filename string) bool {
+	return strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://")
+}
@@ -0,0 +1,1321 @@
+package cge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// unsupportedSchemaKeywords are JSON Schema keywords which cannot be represented in CGE. They are reported and ignored.
+var unsupportedSchemaKeywords = []string{
+	"not", "if", "then", "else", "dependencies", "dependentRequired", "dependentSchemas", "propertyNames",
+	"unevaluatedProperties", "unevaluatedItems", "contains", "minContains", "maxContains", "multipleOf",
+	"uniqueItems", "$dynamicRef", "$recursiveRef",
+}
+
+// keywords are the words which are scanned as keywords and therefore cannot be used as declaration names.
+// Contextual keywords can still be used as property names, enum value names and discriminators.
+var keywords = map[string]TokenType{
+	"name": NAME, "version": VERSION, "import": IMPORT, "config": CONFIG, "event": EVENT, "command": COMMAND, "type": TYPE,
+	"enum": ENUM, "union": UNION, "const": CONST, "extends": EXTENDS, "string": STRING, "bool": BOOL, "int": INT32,
+	"int32": INT32, "int64": INT64, "float32": FLOAT32, "float": FLOAT64, "float64": FLOAT64, "uint": UINT32, "uint32": UINT32,
+	"uint64": UINT64, "bytes": BYTES, "timestamp": TIMESTAMP, "duration": DURATION, "uuid": UUID, "list": LIST, "map": MAP,
+	"true": TRUE, "false": FALSE,
+}
+
+// ImportJSONSchema converts a JSON Schema document into CGE declarations.
+//
+// Object schemas become types, enums of strings or integers become enums and 'oneOf'/'anyOf' of object schemas become unions.
+// Schemas in '$defs' and 'definitions' are declared with their key as the name and the root schema is declared with its title or the name of the game.
+// Nested object schemas and enums are declared with the name of their property.
+// Arrays become lists and 'additionalProperties' become maps.
+//
+// Everything which cannot be represented exactly (e.g. unsupported keywords, renamed properties or dropped default values) is
+// reported in the Warnings of the returned file. The returned syntax tree can be passed to the generators or written to a file with Print.
+// If name is empty, the title of the schema or the name of the file is used.
+func ImportJSONSchema(source io.Reader, filename, name, cgeVersion string) (*File, error) {
+	decoder := json.NewDecoder(source)
+	decoder.UseNumber()
+	value, err := decodeSchemaValue(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	root, ok := value.(*schemaObject)
+	if !ok {
+		return nil, fmt.Errorf("expected JSON Schema object at the top level")
+	}
+
+	i := &schemaImporter{
+		root:         root,
+		declarations: make(map[string]string),
+		names:        make(map[string]bool),
+		resolving:    make(map[string]bool),
+		checked:      make(map[string]bool),
+		nested:       make(map[string]bool),
+		references:   make(map[*PropertyType]string),
+	}
+
+	if name == "" {
+		name = root.str("title")
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	name = schemaIdentifier(name, false)
+	if name == "" {
+		name = "game"
+	}
+
+	metadata := Metadata{
+		Name:         name,
+		CGEVersion:   cgeVersion,
+		NameToken:    Token{Type: IDENTIFIER, Lexeme: name},
+		VersionToken: Token{Type: VERSION_NUMBER, Lexeme: cgeVersion},
+	}
+
+	if i.kind(root) != ALIAS || root.has("type") && !root.has("$defs") && !root.has("definitions") {
+		// the root schema describes a message itself instead of only containing definitions
+		hint := root.str("title")
+		if hint == "" {
+			hint = name
+		}
+		i.declare("#", hint, root)
+	} else {
+		metadata.CommentTokens = schemaComments(root)
+		metadata.Comments = commentLexemes(metadata.CommentTokens)
+	}
+	for _, key := range []string{"$defs", "definitions"} {
+		definitions := root.object(key)
+		if definitions == nil {
+			continue
+		}
+		for _, k := range definitions.keys {
+			pointer := "#/" + key + "/" + escapePointer(k)
+			schema := definitions.values[k]
+			if s, ok := schema.(*schemaObject); ok && i.kind(s) == ALIAS && hasConstraints(s) {
+				// aliases cannot have constraints, so constrained types are inlined when they are referenced
+				continue
+			}
+			i.declare(pointer, k, schema)
+		}
+	}
+
+	for len(i.pending) > 0 {
+		d := i.pending[0]
+		i.pending = i.pending[1:]
+		i.declaration(d)
+	}
+	i.removeDiscriminators()
+	i.breakCycles()
+	i.removeUnusedDeclarations()
+
+	i.objects = append(i.objects, Object{
+		Type:     CONFIG,
+		Implicit: true,
+	})
+
+	return &File{
+		Metadata: metadata,
+		Objects:  i.objects,
+		Warnings: i.warnings,
+	}, nil
+}
+
+type schemaImporter struct {
+	root *schemaObject
+	// declarations maps the JSON pointers of all schemas which are declared to the names of their declarations.
+	declarations map[string]string
+	names        map[string]bool
+	// pending are the declarations which have a name but which haven't been converted yet.
+	pending []pendingDeclaration
+	// resolving contains the references which are currently inlined to detect cycles.
+	resolving map[string]bool
+	// checked contains the JSON pointers of all schemas which have been checked for unsupported keywords.
+	checked map[string]bool
+	// nested contains the names of the declarations of nested object schemas and enums.
+	nested map[string]bool
+	// references maps the types of properties and aliases to the JSON pointers of their schemas to report the references which are replaced by breakCycles.
+	references map[*PropertyType]string
+	unions     []importedUnion
+	objects    []Object
+	warnings   []Diagnostic
+}
+
+type importedUnion struct {
+	pointer string
+	object  Object
+}
+
+type pendingDeclaration struct {
+	pointer string
+	name    string
+	schema  any
+}
+
+// declare returns the name of the declaration of the schema at pointer. New declarations are named after hint.
+func (i *schemaImporter) declare(pointer, hint string, schema any) string {
+	if name, ok := i.declarations[pointer]; ok {
+		return name
+	}
+
+	base := schemaIdentifier(hint, false)
+	if base == "" {
+		base = "unnamed"
+	}
+	name := base
+	for n := 2; i.names[name]; n++ {
+		name = fmt.Sprintf("%s_%d", base, n)
+	}
+
+	i.names[name] = true
+	i.declarations[pointer] = name
+	i.pending = append(i.pending, pendingDeclaration{
+		pointer: pointer,
+		name:    name,
+		schema:  schema,
+	})
+	return name
+}
+
+func (i *schemaImporter) declaration(d pendingDeclaration) {
+	object := Object{
+		Type: TYPE,
+		Name: Token{Type: IDENTIFIER, Lexeme: d.name},
+	}
+
+	s, ok := d.schema.(*schemaObject)
+	if !ok {
+		object.Type = ALIAS
+		object.ValueType, _, _ = i.propertyType(d.pointer, d.schema, d.name)
+		i.references[object.ValueType] = d.pointer
+		i.objects = append(i.objects, object)
+		return
+	}
+
+	object.CommentTokens = schemaComments(s)
+	object.Comments = commentLexemes(object.CommentTokens)
+	object.Attributes, object.Deprecated = schemaDeprecation(s)
+
+	switch i.kind(s) {
+	case ENUM:
+		i.checkKeywords(d.pointer, s)
+		i.enum(d.pointer, s, &object)
+	case UNION:
+		i.checkKeywords(d.pointer, s)
+		i.union(d.pointer, s, &object)
+	case TYPE:
+		i.checkKeywords(d.pointer, s)
+		i.typeDeclaration(d.pointer, s, &object)
+	default:
+		object.Type = ALIAS
+		var constraints []Constraint
+		var nullable bool
+		object.ValueType, constraints, nullable = i.propertyType(d.pointer, s, d.name)
+		i.references[object.ValueType] = d.pointer
+		if len(constraints) > 0 {
+			i.warn(d.pointer, "Aliases cannot have constraints. The constraints were dropped.")
+		}
+		if nullable {
+			i.warn(d.pointer, "'null' is not supported here and was ignored.")
+		}
+	}
+
+	i.objects = append(i.objects, object)
+}
+
+// kind returns the kind of declaration a schema is converted to. ALIAS is returned for all schemas which are not declared unless they are referenced.
+func (i *schemaImporter) kind(s *schemaObject) TokenType {
+	switch {
+	case s == nil:
+		return ALIAS
+	case s.has("$ref"):
+		return ALIAS
+	case enumKind(s) != "":
+		return ENUM
+	case i.isUnion(s):
+		return UNION
+	case isStruct(s):
+		return TYPE
+	default:
+		return ALIAS
+	}
+}
+
+// isStruct reports whether s is an object schema with a fixed set of properties.
+func isStruct(schema any) bool {
+	s, ok := schema.(*schemaObject)
+	if !ok || s == nil {
+		return false
+	}
+	if s.has("properties") || len(s.list("allOf")) > 1 {
+		return true
+	}
+	types, _ := schemaTypes(s)
+	additional, ok := s.values["additionalProperties"].(bool)
+	return len(types) == 1 && types[0] == "object" && ok && !additional && !s.has("patternProperties")
+}
+
+// enumKind returns "string" or "integer" if s is an enum of strings or integers.
+// A 'const' string is treated as an enum with a single value.
+func enumKind(s *schemaObject) string {
+	values, _ := enumValues(s)
+	if len(values) == 0 {
+		return ""
+	}
+	kind := ""
+	for _, v := range values {
+		switch value := v.(type) {
+		case string:
+			if kind == "integer" {
+				return ""
+			}
+			kind = "string"
+		case json.Number:
+			if _, err := strconv.ParseInt(string(value), 10, 64); err != nil || kind == "string" {
+				return ""
+			}
+			kind = "integer"
+		default:
+			return ""
+		}
+	}
+	if kind == "integer" && !s.has("enum") {
+		return ""
+	}
+	return kind
+}
+
+// enumValues returns the values of the 'enum' or 'const' keyword without null.
+func enumValues(s *schemaObject) ([]any, bool) {
+	values := s.list("enum")
+	if !s.has("enum") {
+		value, ok := s.values["const"]
+		if !ok {
+			return nil, false
+		}
+		values = []any{value}
+	}
+	result := make([]any, 0, len(values))
+	nullable := false
+	for _, v := range values {
+		if v == nil {
+			nullable = true
+			continue
+		}
+		result = append(result, v)
+	}
+	return result, nullable
+}
+
+// isUnion reports whether s is a 'oneOf' or 'anyOf' of at least two object schemas.
+func (i *schemaImporter) isUnion(s *schemaObject) bool {
+	_, members := unionMembers(s)
+	if len(members) < 2 {
+		return false
+	}
+	for _, m := range members {
+		ms, ok := m.(*schemaObject)
+		if !ok {
+			return false
+		}
+		if ref, ok := ms.values["$ref"].(string); ok {
+			_, target, ok := i.resolve(ref)
+			if !ok || !isStruct(target) {
+				return false
+			}
+		} else if !isStruct(ms) {
+			return false
+		}
+	}
+	return true
+}
+
+// unionMembers returns the keyword and the members of a 'oneOf' or 'anyOf' without null.
+func unionMembers(s *schemaObject) (string, []any) {
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if !s.has(key) {
+			continue
+		}
+		var members []any
+		for _, m := range s.list(key) {
+			if !isNullSchema(m) {
+				members = append(members, m)
+			}
+		}
+		return key, members
+	}
+	return "", nil
+}
+
+func isNullSchema(schema any) bool {
+	s, ok := schema.(*schemaObject)
+	if !ok {
+		return false
+	}
+	t, ok := s.values["type"].(string)
+	return ok && t == "null" && len(s.keys) == 1
+}
+
+func (i *schemaImporter) typeDeclaration(pointer string, s *schemaObject, object *Object) {
+	type part struct {
+		pointer string
+		schema  *schemaObject
+	}
+	parts := []part{{pointer, s}}
+	for index, a := range s.list("allOf") {
+		p := fmt.Sprintf("%s/allOf/%d", pointer, index)
+		as, ok := a.(*schemaObject)
+		if !ok {
+			i.warn(p, "Boolean schemas are not supported and were ignored.")
+			continue
+		}
+		ref, ok := as.values["$ref"].(string)
+		if !ok {
+			if !isStruct(as) && len(as.keys) > 0 {
+				i.warn(p, "Only object schemas are supported in 'allOf'. The schema was ignored.")
+				continue
+			}
+			i.checkKeywords(p, as)
+			parts = append(parts, part{p, as})
+			continue
+		}
+		targetPointer, target, ok := i.resolve(ref)
+		if !ok || !isStruct(target) {
+			i.warn(p, fmt.Sprintf("'%s' cannot be extended because it is not an object schema. The reference was ignored.", ref))
+			continue
+		}
+		if object.Extends != nil {
+			i.warn(p, fmt.Sprintf("Only one base type is supported. The reference to '%s' was ignored.", ref))
+			continue
+		}
+		object.Extends = &PropertyType{
+			Token: Token{Type: IDENTIFIER, Lexeme: i.declare(targetPointer, pointerName(targetPointer), target)},
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, p := range parts {
+		for _, property := range i.properties(p.pointer, p.schema, object.Name.Lexeme) {
+			if names[property.Name] {
+				i.warn(p.pointer, fmt.Sprintf("Property '%s' is declared more than once. Only the first declaration was kept.", property.Name))
+				continue
+			}
+			names[property.Name] = true
+			object.Properties = append(object.Properties, property)
+		}
+	}
+}
+
+func (i *schemaImporter) properties(pointer string, s *schemaObject, objectName string) []Property {
+	required := make(map[string]bool)
+	for _, r := range s.list("required") {
+		if name, ok := r.(string); ok {
+			required[name] = true
+		}
+	}
+
+	if _, ok := s.values["additionalProperties"].(*schemaObject); ok && s.has("properties") {
+		i.warn(pointer, "'additionalProperties' is not supported together with 'properties' and was ignored.")
+	}
+	if s.has("patternProperties") && s.has("properties") {
+		i.warn(pointer, "'patternProperties' is not supported together with 'properties' and was ignored.")
+	}
+
+	propertySchemas := s.object("properties")
+	if propertySchemas == nil {
+		return nil
+	}
+
+	properties := make([]Property, 0, len(propertySchemas.keys))
+	for _, key := range propertySchemas.keys {
+		p := pointer + "/properties/" + escapePointer(key)
+		schema := propertySchemas.values[key]
+
+		name := schemaIdentifier(key, true)
+		if name == "" {
+			name = "unnamed"
+		}
+		if name != key {
+			i.warn(p, fmt.Sprintf("Property '%s' was renamed to '%s'. The name of the field in the exchanged messages changes accordingly.", key, name))
+		}
+
+		propertyType, constraints, nullable := i.propertyType(p, schema, name)
+		i.references[propertyType] = p
+		property := Property{
+			Name:        name,
+			NameToken:   Token{Type: IDENTIFIER, Lexeme: name},
+			Type:        propertyType,
+			Optional:    !required[key],
+			Constraints: constraints,
+		}
+		if nullable && !property.Optional {
+			property.Optional = true
+			i.warn(p, "'null' is not supported. The property is optional instead.")
+		}
+
+		if ps, ok := schema.(*schemaObject); ok {
+			property.CommentTokens = schemaComments(ps)
+			property.Comments = commentLexemes(property.CommentTokens)
+			property.Attributes, property.Deprecated = schemaDeprecation(ps)
+			if ps.has("default") {
+				i.warn(p, "Default values are only allowed in config and command properties. The default value was dropped.")
+			}
+		}
+
+		properties = append(properties, property)
+	}
+
+	for _, r := range s.list("required") {
+		if name, ok := r.(string); ok && !propertySchemas.has(name) {
+			i.warn(pointer, fmt.Sprintf("Required property '%s' of '%s' is not declared and was ignored.", name, objectName))
+		}
+	}
+
+	return properties
+}
+
+func (i *schemaImporter) enum(pointer string, s *schemaObject, object *Object) {
+	object.Type = ENUM
+	values, nullable := enumValues(s)
+	if nullable {
+		i.warn(pointer, "'null' is not supported as an enum value and was ignored.")
+	}
+
+	used := make(map[string]bool)
+	uniqueName := func(base string) string {
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		return name
+	}
+
+	if enumKind(s) == "string" {
+		for _, v := range values {
+			value := v.(string)
+			base := schemaIdentifier(value, true)
+			if base == "" {
+				base = "value"
+			}
+			name := uniqueName(base)
+			property := Property{
+				Name:      name,
+				NameToken: Token{Type: IDENTIFIER, Lexeme: name},
+			}
+			if name != value {
+				property.Value = &Literal{
+					Token: Token{Type: STRING_LITERAL, Lexeme: strconv.Quote(value)},
+					Value: value,
+				}
+			}
+			object.Properties = append(object.Properties, property)
+		}
+		return
+	}
+
+	object.ValueType = &PropertyType{Token: Token{Type: INT64, Lexeme: "int64"}}
+	if s.str("format") == "int32" {
+		object.ValueType.Token = Token{Type: INT32, Lexeme: "int32"}
+	}
+	next := int64(0)
+	for _, v := range values {
+		number := string(v.(json.Number))
+		value, _ := strconv.ParseInt(number, 10, 64)
+		name := uniqueName("value_" + strings.Replace(number, "-", "minus_", 1))
+		property := Property{
+			Name:      name,
+			NameToken: Token{Type: IDENTIFIER, Lexeme: name},
+		}
+		if value != next {
+			property.Value = &Literal{
+				Token: Token{Type: NUMBER, Lexeme: number},
+				Value: number,
+			}
+		}
+		next = value + 1
+		object.Properties = append(object.Properties, property)
+	}
+	i.warn(pointer, "Integer enum values don't have names in JSON Schema. Names were generated from the values.")
+}
+
+func (i *schemaImporter) union(pointer string, s *schemaObject, object *Object) {
+	object.Type = UNION
+
+	discriminator := "kind"
+	if d := s.object("discriminator"); d != nil && d.str("propertyName") != "" {
+		discriminator = d.str("propertyName")
+	}
+	name := schemaIdentifier(discriminator, true)
+	if name != discriminator {
+		i.warn(pointer, fmt.Sprintf("Discriminator '%s' was renamed to '%s'.", discriminator, name))
+	}
+	object.Discriminator = Token{Type: IDENTIFIER, Lexeme: name}
+
+	key, nonNull := unionMembers(s)
+	if len(nonNull) < len(s.list(key)) {
+		i.warn(pointer, "'null' is not supported as a union member and was ignored.")
+	}
+
+	members := make(map[string]bool)
+	for index, m := range s.list(key) {
+		if isNullSchema(m) {
+			continue
+		}
+		p := fmt.Sprintf("%s/%s/%d", pointer, key, index)
+		ms := m.(*schemaObject)
+		var memberName string
+		if ref, ok := ms.values["$ref"].(string); ok {
+			targetPointer, target, _ := i.resolve(ref)
+			memberName = i.declare(targetPointer, pointerName(targetPointer), target)
+		} else {
+			hint := ms.str("title")
+			if hint == "" {
+				hint = fmt.Sprintf("%s_%d", object.Name.Lexeme, index+1)
+			}
+			memberName = i.declare(p, hint, ms)
+		}
+		if members[memberName] {
+			continue
+		}
+		members[memberName] = true
+
+		memberType := Token{Type: IDENTIFIER, Lexeme: memberName}
+		object.Properties = append(object.Properties, Property{
+			Name:      memberName,
+			NameToken: memberType,
+			Type:      &PropertyType{Token: memberType},
+		})
+	}
+
+	i.unions = append(i.unions, importedUnion{
+		pointer: pointer,
+		object:  *object,
+	})
+	i.warn(pointer, fmt.Sprintf("'%s' was converted to a union. Its members are identified by the name of their type in the property '%s'.", key, name))
+}
+
+// removeDiscriminators removes the discriminator properties of all unions from their members, because the discriminator is added by the union.
+func (i *schemaImporter) removeDiscriminators() {
+	for _, union := range i.unions {
+		discriminator := union.object.Discriminator.Lexeme
+		for _, member := range union.object.Properties {
+			for index, object := range i.objects {
+				if object.Name.Lexeme != member.Name || object.Type != TYPE {
+					continue
+				}
+				properties := make([]Property, 0, len(object.Properties))
+				for _, p := range object.Properties {
+					if p.Name == discriminator {
+						i.warn(union.pointer, fmt.Sprintf("Property '%s' of '%s' was removed because it is the discriminator of union '%s'.", discriminator, object.Name.Lexeme, union.object.Name.Lexeme))
+						continue
+					}
+					properties = append(properties, p)
+				}
+				i.objects[index].Properties = properties
+			}
+		}
+	}
+}
+
+// breakCycles replaces the references which close a declaration cycle with 'string', because declarations cannot contain themselves in CGE.
+// References in lists and maps don't form cycles and are kept.
+func (i *schemaImporter) breakCycles() {
+	objects := make(map[string]*Object, len(i.objects))
+	for index := range i.objects {
+		objects[i.objects[index].Name.Lexeme] = &i.objects[index]
+	}
+
+	type visit struct {
+		name string
+		// reference is the property or alias type which refers to the declaration. It is nil for base types and union members, which cannot be replaced.
+		reference *PropertyType
+	}
+	var stack []visit
+	done := make(map[string]bool)
+
+	var check func(name string, reference *PropertyType)
+	check = func(name string, reference *PropertyType) {
+		object, ok := objects[name]
+		if !ok || done[name] {
+			return
+		}
+		for index, v := range stack {
+			if v.name != name {
+				continue
+			}
+			// the cycle is broken at the last reference which can be replaced
+			for j := len(stack) - 1; reference == nil && j > index; j-- {
+				reference = stack[j].reference
+			}
+			if reference != nil {
+				i.warn(i.references[reference], fmt.Sprintf("Recursive reference '%s' is not supported. Using 'string' instead.", name))
+				*reference = *primitiveType(STRING)
+			}
+			return
+		}
+
+		stack = append(stack, visit{name: name, reference: reference})
+		if object.Type == ALIAS && object.ValueType.Token.Type == IDENTIFIER {
+			check(object.ValueType.Token.Lexeme, object.ValueType)
+		}
+		if object.Extends != nil {
+			check(object.Extends.Token.Lexeme, nil)
+		}
+		for _, p := range object.Properties {
+			if p.Type == nil || p.Type.Token.Type != IDENTIFIER {
+				continue
+			}
+			if object.Type == UNION {
+				check(p.Type.Token.Lexeme, nil)
+			} else {
+				check(p.Type.Token.Lexeme, p.Type)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		done[name] = true
+	}
+
+	for _, o := range i.objects {
+		check(o.Name.Lexeme, nil)
+	}
+}
+
+// removeUnusedDeclarations removes the declarations of nested schemas which are not used anymore because their property was removed by removeDiscriminators.
+func (i *schemaImporter) removeUnusedDeclarations() {
+	for {
+		used := make(map[string]bool)
+		Inspect(&File{Objects: i.objects}, func(node Node) bool {
+			if t, ok := node.(*PropertyType); ok && t.Token.Type == IDENTIFIER {
+				used[t.Token.Lexeme] = true
+			}
+			return true
+		})
+
+		objects := make([]Object, 0, len(i.objects))
+		for _, o := range i.objects {
+			if !i.nested[o.Name.Lexeme] || used[o.Name.Lexeme] {
+				objects = append(objects, o)
+			}
+		}
+		if len(objects) == len(i.objects) {
+			return
+		}
+		i.objects = objects
+	}
+}
+
+// propertyType converts a schema which is used as the type of a property, list element, map value or alias.
+// It returns the type, the constraints of the schema and whether the schema allows null.
+func (i *schemaImporter) propertyType(pointer string, schema any, hint string) (*PropertyType, []Constraint, bool) {
+	s, ok := schema.(*schemaObject)
+	if !ok {
+		i.warn(pointer, "Boolean schemas are not supported. Using 'string' instead.")
+		return primitiveType(STRING), nil, false
+	}
+
+	if ref, ok := s.values["$ref"].(string); ok {
+		return i.reference(pointer, ref)
+	}
+
+	switch i.kind(s) {
+	case ENUM, UNION, TYPE:
+		if title := s.str("title"); title != "" {
+			hint = title
+		}
+		name := i.declare(pointer, hint, s)
+		i.nested[name] = true
+		return &PropertyType{
+			Token: Token{Type: IDENTIFIER, Lexeme: name},
+		}, nil, false
+	}
+
+	i.checkKeywords(pointer, s)
+
+	if all := s.list("allOf"); len(all) == 1 {
+		return i.propertyType(pointer+"/allOf/0", all[0], hint)
+	}
+	if key, members := unionMembers(s); key != "" {
+		if len(members) == 1 {
+			for index, m := range s.list(key) {
+				if !isNullSchema(m) {
+					propertyType, constraints, _ := i.propertyType(fmt.Sprintf("%s/%s/%d", pointer, key, index), m, hint)
+					return propertyType, constraints, len(s.list(key)) > 1
+				}
+			}
+		}
+		i.warn(pointer, fmt.Sprintf("'%s' is only supported for object schemas. Using 'string' instead.", key))
+		return primitiveType(STRING), nil, false
+	}
+	if s.has("enum") {
+		i.warn(pointer, "Enum values must either all be strings or all be integers. The values are not enforced.")
+	} else if s.has("const") {
+		i.warn(pointer, "'const' is only supported for strings. The value is not enforced.")
+	}
+
+	types, nullable := schemaTypes(s)
+	if len(types) == 0 {
+		switch {
+		case s.has("properties") || s.has("additionalProperties") || s.has("patternProperties"):
+			types = []string{"object"}
+		case s.has("items") || s.has("prefixItems"):
+			types = []string{"array"}
+		case s.has("minimum") || s.has("maximum") || s.has("exclusiveMinimum") || s.has("exclusiveMaximum"):
+			types = []string{"number"}
+		case s.has("minLength") || s.has("maxLength") || s.has("pattern") || s.has("format"):
+			types = []string{"string"}
+		case s.has("const") || len(s.list("enum")) > 0:
+			values, _ := enumValues(s)
+			if len(values) > 0 {
+				types = []string{valueType(values[0])}
+			} else {
+				types = []string{"null"}
+			}
+		default:
+			i.warn(pointer, "Schemas without a type are not supported. Using 'string' instead.")
+			return primitiveType(STRING), nil, nullable
+		}
+	}
+	if len(types) > 1 {
+		i.warn(pointer, fmt.Sprintf("Multiple types (%s) are not supported. Using 'string' instead.", strings.Join(types, ", ")))
+		return primitiveType(STRING), nil, nullable
+	}
+
+	switch types[0] {
+	case "string":
+		propertyType, constraints := i.stringType(pointer, s)
+		return propertyType, constraints, nullable
+	case "integer":
+		propertyType := primitiveType(INT64)
+		switch s.str("format") {
+		case "int32":
+			propertyType = primitiveType(INT32)
+		case "uint32":
+			propertyType = primitiveType(UINT32)
+		case "uint64":
+			propertyType = primitiveType(UINT64)
+		}
+		return propertyType, i.numberConstraints(pointer, s, true), nullable
+	case "number":
+		propertyType := primitiveType(FLOAT64)
+		if s.str("format") == "float" {
+			propertyType = primitiveType(FLOAT32)
+		}
+		return propertyType, i.numberConstraints(pointer, s, false), nullable
+	case "boolean":
+		return primitiveType(BOOL), nil, nullable
+	case "array":
+		propertyType, constraints := i.listType(pointer, s, hint)
+		return propertyType, constraints, nullable
+	case "object":
+		propertyType, constraints := i.mapType(pointer, s, hint)
+		return propertyType, constraints, nullable
+	case "null":
+		i.warn(pointer, "'null' is not supported. Using 'string' instead.")
+		return primitiveType(STRING), nil, true
+	default:
+		i.warn(pointer, fmt.Sprintf("Unknown type '%s'. Using 'string' instead.", types[0]))
+		return primitiveType(STRING), nil, nullable
+	}
+}
+
+// reference converts a '$ref'. Declared schemas are referenced by name and all other schemas are inlined.
+func (i *schemaImporter) reference(pointer, ref string) (*PropertyType, []Constraint, bool) {
+	targetPointer, target, ok := i.resolve(ref)
+	if !ok {
+		if strings.HasPrefix(ref, "#") {
+			i.warn(pointer, fmt.Sprintf("Cannot resolve reference '%s'. Using 'string' instead.", ref))
+		} else {
+			i.warn(pointer, fmt.Sprintf("Only references within the same document are supported. Using 'string' instead of '%s'.", ref))
+		}
+		return primitiveType(STRING), nil, false
+	}
+
+	name, declared := i.declarations[targetPointer]
+	if s, ok := target.(*schemaObject); !declared && ok && i.kind(s) != ALIAS {
+		name, declared = i.declare(targetPointer, pointerName(targetPointer), s), true
+	}
+	if declared {
+		return &PropertyType{
+			Token: Token{Type: IDENTIFIER, Lexeme: name},
+		}, nil, false
+	}
+
+	if i.resolving[targetPointer] {
+		i.warn(pointer, fmt.Sprintf("Recursive reference '%s' is not supported. Using 'string' instead.", ref))
+		return primitiveType(STRING), nil, false
+	}
+	i.resolving[targetPointer] = true
+	defer delete(i.resolving, targetPointer)
+	return i.propertyType(targetPointer, target, pointerName(targetPointer))
+}
+
+// resolve returns the canonical JSON pointer and the schema of a local reference.
+func (i *schemaImporter) resolve(ref string) (string, any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return "", nil, false
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+	var current any = i.root
+	pointer := "#"
+	if path != "" {
+		for _, segment := range strings.Split(path, "/") {
+			key := strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+			switch value := current.(type) {
+			case *schemaObject:
+				if !value.has(key) {
+					return "", nil, false
+				}
+				current = value.values[key]
+			case []any:
+				index, err := strconv.Atoi(key)
+				if err != nil || index < 0 || index >= len(value) {
+					return "", nil, false
+				}
+				current = value[index]
+			default:
+				return "", nil, false
+			}
+			pointer += "/" + escapePointer(key)
+		}
+	}
+	return pointer, current, true
+}
+
+func (i *schemaImporter) stringType(pointer string, s *schemaObject) (*PropertyType, []Constraint) {
+	propertyType := primitiveType(STRING)
+	switch {
+	case s.str("format") == "date-time":
+		propertyType = primitiveType(TIMESTAMP)
+	case s.str("format") == "uuid":
+		propertyType = primitiveType(UUID)
+	case s.str("format") == "byte" || s.str("contentEncoding") == "base64":
+		propertyType = primitiveType(BYTES)
+	}
+
+	if propertyType.Token.Type != STRING {
+		if s.has("minLength") || s.has("maxLength") || s.has("pattern") {
+			i.warn(pointer, fmt.Sprintf("Length constraints and patterns are not supported for '%s'. The constraints were dropped.", propertyType.Token.Lexeme))
+		}
+		return propertyType, nil
+	}
+
+	constraints := i.countConstraints(pointer, s, "minLength", "min_len", "maxLength", "max_len")
+	if s.has("pattern") {
+		pattern := s.str("pattern")
+		if _, err := regexp.Compile(pattern); err != nil {
+			i.warn(pointer, fmt.Sprintf("Pattern '%s' is not supported: %s. The constraint was dropped.", pattern, err))
+		} else {
+			constraints = append(constraints, Constraint{
+				Name: Token{Type: IDENTIFIER, Lexeme: "pattern"},
+				Value: Literal{
+					Token: Token{Type: STRING_LITERAL, Lexeme: strconv.Quote(pattern)},
+					Value: pattern,
+				},
+			})
+		}
+	}
+	return propertyType, constraints
+}
+
+func (i *schemaImporter) listType(pointer string, s *schemaObject, hint string) (*PropertyType, []Constraint) {
+	propertyType := &PropertyType{Token: Token{Type: LIST, Lexeme: "list"}}
+	_, tuple := s.values["items"].([]any)
+	switch {
+	case tuple || s.has("prefixItems"):
+		i.warn(pointer, "Tuples are not supported. Using 'list<string>' instead.")
+		propertyType.Generic = primitiveType(STRING)
+	case !s.has("items"):
+		i.warn(pointer, "Arrays without 'items' are not supported. Using 'list<string>' instead.")
+		propertyType.Generic = primitiveType(STRING)
+	default:
+		generic, constraints, nullable := i.propertyType(pointer+"/items", s.values["items"], hint+"_item")
+		if len(constraints) > 0 {
+			i.warn(pointer+"/items", "Constraints of list elements are not supported. The constraints were dropped.")
+		}
+		if nullable {
+			i.warn(pointer+"/items", "'null' list elements are not supported and were ignored.")
+		}
+		propertyType.Generic = generic
+	}
+	return propertyType, i.countConstraints(pointer, s, "minItems", "min_items", "maxItems", "max_items")
+}
+
+func (i *schemaImporter) mapType(pointer string, s *schemaObject, hint string) (*PropertyType, []Constraint) {
+	propertyType := &PropertyType{Token: Token{Type: MAP, Lexeme: "map"}}
+
+	valuePointer := pointer + "/additionalProperties"
+	value, ok := s.values["additionalProperties"].(*schemaObject)
+	if patterns := s.object("patternProperties"); !ok && patterns != nil && len(patterns.keys) > 0 {
+		i.warn(pointer, "'patternProperties' is not supported. It was converted to a map without restrictions on the keys.")
+		valuePointer = pointer + "/patternProperties/" + escapePointer(patterns.keys[0])
+		value, ok = patterns.values[patterns.keys[0]].(*schemaObject)
+		if len(patterns.keys) > 1 {
+			i.warn(pointer, "Only the first pattern of 'patternProperties' was used.")
+		}
+	}
+
+	if ok {
+		generic, constraints, nullable := i.propertyType(valuePointer, value, hint+"_value")
+		if len(constraints) > 0 {
+			i.warn(valuePointer, "Constraints of map values are not supported. The constraints were dropped.")
+		}
+		if nullable {
+			i.warn(valuePointer, "'null' map values are not supported and were ignored.")
+		}
+		propertyType.Generic = generic
+	} else {
+		i.warn(pointer, "Objects with arbitrary properties are not supported. Using 'map<string>' instead.")
+		propertyType.Generic = primitiveType(STRING)
+	}
+
+	return propertyType, i.countConstraints(pointer, s, "minProperties", "min_items", "maxProperties", "max_items")
+}
+
+// countConstraints converts the JSON Schema keywords minKeyword and maxKeyword, which must be non-negative integers, into the constraints minName and maxName.
+func (i *schemaImporter) countConstraints(pointer string, s *schemaObject, minKeyword, minName, maxKeyword, maxName string) []Constraint {
+	var constraints []Constraint
+	for _, c := range [][2]string{{minKeyword, minName}, {maxKeyword, maxName}} {
+		if !s.has(c[0]) {
+			continue
+		}
+		number, ok := s.values[c[0]].(json.Number)
+		if _, err := strconv.ParseUint(string(number), 10, 32); !ok || err != nil {
+			i.warn(pointer, fmt.Sprintf("'%s' must be a non-negative integer. The constraint was dropped.", c[0]))
+			continue
+		}
+		constraints = append(constraints, numberConstraint(c[1], string(number)))
+	}
+	return constraints
+}
+
+// numberConstraints converts the bounds of a number. Exclusive bounds of integers are converted to the next inclusive bound.
+func (i *schemaImporter) numberConstraints(pointer string, s *schemaObject, integer bool) []Constraint {
+	var constraints []Constraint
+	for _, b := range []struct {
+		inclusive, exclusive, name string
+		direction                  int64
+	}{{"minimum", "exclusiveMinimum", "min", 1}, {"maximum", "exclusiveMaximum", "max", -1}} {
+		value, ok := s.values[b.inclusive].(json.Number)
+		// draft 4 uses a boolean next to the bound instead of an exclusive bound
+		exclusive, _ := s.values[b.exclusive].(bool)
+		if number, isNumber := s.values[b.exclusive].(json.Number); isNumber {
+			if !ok || compareNumbers(number, value)*b.direction >= 0 {
+				value, ok, exclusive = number, true, true
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		lexeme, valid := integerBound(value, exclusive, b.direction)
+		if !integer {
+			lexeme, valid = numberLexeme(value)
+			if exclusive {
+				i.warn(pointer, fmt.Sprintf("'%s' is not supported for floating point numbers. It was converted to an inclusive bound.", b.exclusive))
+			}
+		}
+		if !valid {
+			i.warn(pointer, fmt.Sprintf("Invalid bound '%s'. The constraint was dropped.", value))
+			continue
+		}
+		constraints = append(constraints, numberConstraint(b.name, lexeme))
+	}
+	return constraints
+}
+
+// integerBound returns the inclusive integer bound of value. direction is 1 for lower bounds and -1 for upper bounds.
+func integerBound(value json.Number, exclusive bool, direction int64) (string, bool) {
+	if n, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+		if exclusive {
+			n += direction
+		}
+		return strconv.FormatInt(n, 10), true
+	}
+	f, err := strconv.ParseFloat(string(value), 64)
+	if err != nil || math.IsInf(f, 0) {
+		return "", false
+	}
+	rounded := math.Ceil(f)
+	if direction < 0 {
+		rounded = math.Floor(f)
+	}
+	if exclusive && rounded == f {
+		rounded += float64(direction)
+	}
+	return strconv.FormatFloat(rounded, 'f', 0, 64), true
+}
+
+// numberLexeme returns value in the number syntax of CGE, which doesn't support exponents.
+func numberLexeme(value json.Number) (string, bool) {
+	if !strings.ContainsAny(string(value), "eE") {
+		return string(value), true
+	}
+	f, err := strconv.ParseFloat(string(value), 64)
+	if err != nil || math.IsInf(f, 0) {
+		return "", false
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), true
+}
+
+func compareNumbers(a, b json.Number) int64 {
+	x, _ := strconv.ParseFloat(string(a), 64)
+	y, _ := strconv.ParseFloat(string(b), 64)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func numberConstraint(name, value string) Constraint {
+	return Constraint{
+		Name: Token{Type: IDENTIFIER, Lexeme: name},
+		Value: Literal{
+			Token: Token{Type: NUMBER, Lexeme: value},
+			Value: value,
+		},
+	}
+}
+
+// checkKeywords reports all unsupported keywords of s.
+func (i *schemaImporter) checkKeywords(pointer string, s *schemaObject) {
+	if i.checked[pointer] {
+		return
+	}
+	i.checked[pointer] = true
+	for _, k := range unsupportedSchemaKeywords {
+		if s.has(k) {
+			i.warn(pointer, fmt.Sprintf("'%s' is not supported and was ignored.", k))
+		}
+	}
+}
+
+func (i *schemaImporter) warn(pointer, message string) {
+	i.warnings = append(i.warnings, Diagnostic{
+		Severity: SeverityWarning,
+		Code:     CodeLossyConversion,
+		Message:  fmt.Sprintf("%s: %s", pointer, message),
+	})
+}
+
+// schemaTypes returns the values of the 'type' keyword without "null".
+func schemaTypes(s *schemaObject) ([]string, bool) {
+	var types []any
+	switch t := s.values["type"].(type) {
+	case string:
+		types = []any{t}
+	case []any:
+		types = t
+	}
+	result := make([]string, 0, len(types))
+	nullable := false
+	for _, t := range types {
+		name, _ := t.(string)
+		if name == "null" && len(types) > 1 {
+			nullable = true
+			continue
+		}
+		result = append(result, name)
+	}
+	return result, nullable
+}
+
+// valueType returns the JSON Schema type of a JSON value.
+func valueType(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case *schemaObject:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+// hasConstraints reports whether s uses keywords which are converted to constraints.
+func hasConstraints(s *schemaObject) bool {
+	for _, k := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "pattern", "minItems", "maxItems", "minProperties", "maxProperties"} {
+		if s.has(k) {
+			return true
+		}
+	}
+	return false
+}
+
+func primitiveType(tokenType TokenType) *PropertyType {
+	return &PropertyType{
+		Token: Token{Type: tokenType, Lexeme: strings.ToLower(string(tokenType))},
+	}
+}
+
+// schemaComments returns the lines of the description of a schema as comments.
+func schemaComments(s *schemaObject) []Token {
+	description := strings.TrimSpace(s.str("description"))
+	if description == "" {
+		return nil
+	}
+	var comments []Token
+	for _, line := range strings.Split(description, "\n") {
+		comments = append(comments, Token{Type: COMMENT, Lexeme: strings.TrimSpace(line)})
+	}
+	return comments
+}
+
+// schemaDeprecation returns a '@deprecated' attribute if the schema is deprecated.
+func schemaDeprecation(s *schemaObject) ([]Attribute, *Deprecation) {
+	if deprecated, _ := s.values["deprecated"].(bool); !deprecated {
+		return nil, nil
+	}
+	name := Token{Type: IDENTIFIER, Lexeme: "deprecated"}
+	return []Attribute{{Name: name}}, &Deprecation{Token: name}
+}
+
+// schemaIdentifier converts text to a valid snake_case identifier, e.g. 'playerName' to 'player_name'.
+// Keywords get a trailing underscore, except for contextual keywords if the identifier is the name of a member (property, enum value or discriminator).
+// The result is empty if text doesn't contain any letters or digits.
+func schemaIdentifier(text string, member bool) string {
+	isUpper := func(r rune) bool { return r >= 'A' && r <= 'Z' }
+	isLower := func(r rune) bool { return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' }
+
+	var b strings.Builder
+	// separate is true if the next word has to be separated with an underscore
+	separate := false
+	runes := []rune(text)
+	for index, r := range runes {
+		if !isUpper(r) && !isLower(r) && r != '_' {
+			separate = true
+			continue
+		}
+		if isUpper(r) && index > 0 {
+			previous := runes[index-1]
+			nextLower := index+1 < len(runes) && isLower(runes[index+1])
+			separate = separate || isLower(previous) || isUpper(previous) && nextLower
+		}
+		if separate && b.Len() > 0 && r != '_' {
+			b.WriteRune('_')
+		}
+		separate = false
+		if isUpper(r) {
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+
+	name := b.String()
+	if name == "" {
+		return ""
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	if keyword, ok := keywords[name]; ok {
+		if _, contextual := contextualKeywords[keyword]; !member || !contextual {
+			name += "_"
+		}
+	}
+	return name
+}
+
+// pointerName returns the last segment of a JSON pointer or the title of the root schema.
+func pointerName(pointer string) string {
+	index := strings.LastIndex(pointer, "/")
+	if index == -1 {
+		return "root"
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(pointer[index+1:], "~1", "/"), "~0", "~")
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// schemaObject is a JSON object which keeps the order of its keys, so declarations and properties are imported in the order of the schema.
+type schemaObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (s *schemaObject) has(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+func (s *schemaObject) str(key string) string {
+	value, _ := s.values[key].(string)
+	return value
+}
+
+func (s *schemaObject) object(key string) *schemaObject {
+	value, _ := s.values[key].(*schemaObject)
+	return value
+}
+
+func (s *schemaObject) list(key string) []any {
+	value, _ := s.values[key].([]any)
+	return value
+}
+
+// decodeSchemaValue decodes the next JSON value. Objects are decoded as *schemaObject and numbers as json.Number.
+func decodeSchemaValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := &schemaObject{
+			values: make(map[string]any),
+		}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeSchemaValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			name := key.(string)
+			if !object.has(name) {
+				object.keys = append(object.keys, name)
+			}
+			object.values[name] = value
+		}
+		_, err = decoder.Token()
+		return object, err
+	default:
+		list := make([]any, 0)
+		for decoder.More() {
+			value, err := decodeSchemaValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = decoder.Token()
+		return list, err
+	}
+}
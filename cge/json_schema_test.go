@@ -0,0 +1,383 @@
+package cge
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestImportJSONSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{
+			name: "type mapping",
+			schema: `{"title": "game", "type": "object", "properties": {
+				"s": {"type": "string", "minLength": 1, "maxLength": 5, "pattern": "^a"},
+				"i": {"type": "integer", "minimum": 0, "exclusiveMaximum": 10},
+				"f": {"type": "number"},
+				"b": {"type": "boolean"},
+				"t": {"type": "string", "format": "date-time"},
+				"u": {"type": "string", "format": "uuid"},
+				"by": {"type": "string", "contentEncoding": "base64"},
+				"l": {"type": "array", "items": {"type": "string"}, "maxItems": 3},
+				"m": {"type": "object", "additionalProperties": {"type": "integer"}},
+				"o": {"type": "object", "properties": {"x": {"type": "number"}}, "required": ["x"]},
+				"opt": {"type": "string"}
+			}, "required": ["s", "i", "f", "b", "t", "u", "by", "l", "m", "o"]}`,
+			expected: `type game {
+	s: string [min_len=1, max_len=5, pattern="^a"],
+	i: int64 [min=0, max=9],
+	f: float64,
+	b: bool,
+	t: timestamp,
+	u: uuid,
+	by: bytes,
+	l: list<string> [max_items=3],
+	m: map<int64>,
+	o: o,
+	opt?: string
+}
+
+type o {
+	x: float64
+}
+`,
+		},
+		{
+			name: "references",
+			schema: `{"title": "game", "type": "object", "properties": {
+				"p": {"$ref": "#/$defs/player"},
+				"ps": {"type": "array", "items": {"$ref": "#/$defs/player"}},
+				"id": {"$ref": "#/definitions/id"}
+			}, "required": ["p", "ps", "id"],
+			"$defs": {"player": {"type": "object", "properties": {"id": {"$ref": "#/definitions/id"}}, "required": ["id"]}},
+			"definitions": {"id": {"type": "string", "format": "uuid"}}}`,
+			expected: `type game {
+	p: player,
+	ps: list<player>,
+	id: id
+}
+
+type player {
+	id: id
+}
+
+type id = uuid
+`,
+		},
+		{
+			name: "extends",
+			schema: `{"$defs": {
+				"base": {"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]},
+				"player": {"allOf": [{"$ref": "#/$defs/base"}], "type": "object", "properties": {"score": {"type": "integer"}}, "required": ["score"]}
+			}}`,
+			expected: `type base {
+	id: string
+}
+
+type player extends base {
+	score: int64
+}
+`,
+		},
+		{
+			name: "union",
+			schema: `{"title": "game", "type": "object", "properties": {
+				"shape": {"oneOf": [{"$ref": "#/$defs/circle"}, {"$ref": "#/$defs/square"}]}
+			}, "required": ["shape"],
+			"$defs": {
+				"circle": {"type": "object", "properties": {"kind": {"const": "circle"}, "r": {"type": "number"}}, "required": ["kind", "r"]},
+				"square": {"type": "object", "properties": {"kind": {"const": "square"}, "a": {"type": "number"}}, "required": ["kind", "a"]}
+			}}`,
+			expected: `type game {
+	shape: shape
+}
+
+type circle {
+	r: float64
+}
+
+type square {
+	a: float64
+}
+
+union shape(kind) {
+	circle,
+	square
+}
+`,
+		},
+		{
+			name: "enums",
+			schema: `{"title": "game", "type": "object", "properties": {
+				"color": {"enum": ["red", "Dark-Blue"]},
+				"level": {"enum": [1, 2, 5]},
+				"mode": {"const": "fast"}
+			}, "required": ["color", "level", "mode"]}`,
+			expected: `type game {
+	color: color,
+	level: level,
+	mode: mode
+}
+
+enum color {
+	red,
+	dark_blue = "Dark-Blue"
+}
+
+enum level: int64 {
+	value_1 = 1,
+	value_2,
+	value_5 = 5
+}
+
+enum mode {
+	fast
+}
+`,
+		},
+		{
+			name: "union without discriminator",
+			schema: `{"title": "game", "type": "object", "properties": {
+				"shape": {"oneOf": [{"$ref": "#/$defs/circle"}, {"$ref": "#/$defs/square"}]}
+			}, "required": ["shape"],
+			"$defs": {
+				"circle": {"type": "object", "properties": {"r": {"type": "number"}}, "required": ["r"]},
+				"square": {"type": "object", "properties": {"a": {"type": "number"}}, "required": ["a"]}
+			}}`,
+			expected: `type game {
+	shape: shape
+}
+
+type circle {
+	r: float64
+}
+
+type square {
+	a: float64
+}
+
+union shape(kind) {
+	circle,
+	square
+}
+`,
+		},
+		{
+			name: "recursive object",
+			schema: `{"title": "game", "type": "object", "properties": {"head": {"$ref": "#/$defs/node"}}, "required": ["head"],
+			"$defs": {"node": {"type": "object", "properties": {"value": {"type": "integer"}, "next": {"$ref": "#/$defs/node"}, "children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}, "required": ["value"]}}}`,
+			expected: `type game {
+	head: node
+}
+
+type node {
+	value: int64,
+	next?: string,
+	children?: list<node>
+}
+`,
+		},
+		{
+			name:   "recursive aliases",
+			schema: `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`,
+			expected: `type a = b
+
+type b = string
+`,
+		},
+		{
+			name: "recursive union",
+			schema: `{"$defs": {
+				"tree": {"oneOf": [{"$ref": "#/$defs/leaf"}, {"$ref": "#/$defs/branch"}], "discriminator": {"propertyName": "kind"}},
+				"leaf": {"type": "object", "properties": {"value": {"type": "integer"}}, "required": ["value"]},
+				"branch": {"type": "object", "properties": {"left": {"$ref": "#/$defs/tree"}, "right": {"$ref": "#/$defs/tree"}}, "required": ["left", "right"]}
+			}}`,
+			expected: `union tree(kind) {
+	leaf,
+	branch
+}
+
+type leaf {
+	value: int64
+}
+
+type branch {
+	left: string,
+	right: string
+}
+`,
+		},
+		{
+			name: "keywords as member names",
+			schema: `{"title": "game", "type": "object", "properties": {
+				"timestamp": {"type": "string"},
+				"import": {"enum": ["uuid", "true"]},
+				"s": {"oneOf": [{"$ref": "#/$defs/a"}, {"$ref": "#/$defs/b"}], "discriminator": {"propertyName": "const"}}
+			}, "required": ["timestamp", "import", "s"],
+			"$defs": {
+				"a": {"type": "object", "properties": {"x": {"type": "number"}}, "required": ["x"]},
+				"b": {"type": "object", "properties": {"y": {"type": "number"}}, "required": ["y"]}
+			}}`,
+			expected: `type game {
+	timestamp: string,
+	import: import_,
+	s: s
+}
+
+type a {
+	x: float64
+}
+
+type b {
+	y: float64
+}
+
+enum import_ {
+	uuid,
+	true
+}
+
+union s(const) {
+	a,
+	b
+}
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, _ := importSchema(t, test.schema)
+			expected := "name game\nversion " + LatestVersion + "\n\n" + test.expected
+			if source != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, source)
+			}
+			if _, _, errs := Parse(strings.NewReader(source), "dev"); len(errs) > 0 {
+				t.Errorf("failed to parse the imported declarations: %v", errs)
+			}
+		})
+	}
+}
+
+func TestImportJSONSchemaWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected []string
+	}{
+		{
+			name:     "lossless",
+			schema:   `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			expected: nil,
+		},
+		{
+			name:     "unsupported keyword",
+			schema:   `{"type": "object", "properties": {"a": {"type": "integer", "multipleOf": 2}}}`,
+			expected: []string{"#/properties/a: 'multipleOf' is not supported and was ignored."},
+		},
+		{
+			name: "unsupported keywords",
+			schema: `{"type": "object", "properties": {
+				"a": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+				"b": {"type": "string", "not": {"const": "x"}},
+				"c": {"type": "object", "properties": {"d": {"type": "string"}}, "if": {"required": ["d"]}, "then": {}}
+			}}`,
+			expected: []string{
+				"#/properties/a: 'uniqueItems' is not supported and was ignored.",
+				"#/properties/b: 'not' is not supported and was ignored.",
+				"#/properties/c: 'if' is not supported and was ignored.",
+				"#/properties/c: 'then' is not supported and was ignored.",
+			},
+		},
+		{
+			name:     "oneOf without discriminator",
+			schema:   `{"type": "object", "properties": {"a": {"oneOf": [{"type": "object", "properties": {"x": {"type": "string"}}}, {"type": "object", "properties": {"y": {"type": "string"}}}]}}}`,
+			expected: []string{"#/properties/a: 'oneOf' was converted to a union. Its members are identified by the name of their type in the property 'kind'."},
+		},
+		{
+			name:     "recursive reference",
+			schema:   `{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}}`,
+			expected: []string{"#/$defs/node/properties/next: Recursive reference 'node' is not supported. Using 'string' instead."},
+		},
+		{
+			name:     "recursive inline reference",
+			schema:   `{"type": "object", "properties": {"a": {"$ref": "#/$defs/l"}}, "$defs": {"l": {"type": "array", "maxItems": 2, "items": {"$ref": "#/$defs/l"}}}}`,
+			expected: []string{"#/$defs/l/items: Recursive reference '#/$defs/l' is not supported. Using 'string' instead."},
+		},
+		{
+			name:   "renamed properties",
+			schema: `{"type": "object", "properties": {"name": {"type": "string"}, "playerName": {"type": "string"}}}`,
+			expected: []string{
+				"#/properties/name: Property 'name' was renamed to 'name_'. The name of the field in the exchanged messages changes accordingly.",
+				"#/properties/playerName: Property 'playerName' was renamed to 'player_name'. The name of the field in the exchanged messages changes accordingly.",
+			},
+		},
+		{
+			name:     "default value",
+			schema:   `{"type": "object", "properties": {"a": {"type": "integer", "default": 3}}}`,
+			expected: []string{"#/properties/a: Default values are only allowed in config and command properties. The default value was dropped."},
+		},
+		{
+			name:     "nullable property",
+			schema:   `{"type": "object", "properties": {"a": {"type": ["string", "null"]}}, "required": ["a"]}`,
+			expected: []string{"#/properties/a: 'null' is not supported. The property is optional instead."},
+		},
+		{
+			name:     "tuple",
+			schema:   `{"type": "object", "properties": {"a": {"type": "array", "prefixItems": [{"type": "string"}]}}}`,
+			expected: []string{"#/properties/a: Tuples are not supported. Using 'list<string>' instead."},
+		},
+		{
+			name:     "external reference",
+			schema:   `{"type": "object", "properties": {"a": {"$ref": "other.json#/$defs/a"}}}`,
+			expected: []string{"#/properties/a: Only references within the same document are supported. Using 'string' instead of 'other.json#/$defs/a'."},
+		},
+		{
+			name:     "integer enum",
+			schema:   `{"type": "object", "properties": {"a": {"enum": [1, 2]}}}`,
+			expected: []string{"#/properties/a: Integer enum values don't have names in JSON Schema. Names were generated from the values."},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, warnings := importSchema(t, test.schema)
+			messages := make([]string, len(warnings))
+			for i, w := range warnings {
+				messages[i] = w.Message
+				if w.Severity != SeverityWarning || w.Code != CodeLossyConversion {
+					t.Errorf("expected a %s warning, got %s %s", CodeLossyConversion, w.Severity, w.Code)
+				}
+			}
+			if strings.Join(messages, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("expected warnings:\n%s\ngot:\n%s", strings.Join(test.expected, "\n"), strings.Join(messages, "\n"))
+			}
+		})
+	}
+}
+
+func TestImportJSONSchemaErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON": `{`,
+		"no object":    `[]`,
+	}
+	for name, schema := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ImportJSONSchema(strings.NewReader(schema), "game.json", "", LatestVersion); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+// importSchema imports schema and returns the printed declarations and the warnings.
+func importSchema(t *testing.T, schema string) (string, []Diagnostic) {
+	t.Helper()
+	file, err := ImportJSONSchema(bytes.NewBufferString(schema), "game.json", "", LatestVersion)
+	if err != nil {
+		t.Fatalf("failed to import schema: %s", err)
+	}
+	return string(Print(file.Metadata, file.Objects)), file.Warnings
+}
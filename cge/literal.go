@@ -0,0 +1,169 @@
+package cge
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Literal is a literal value like the default value of a property.
+type Literal struct {
+	// Token is a STRING_LITERAL, NUMBER, TRUE, FALSE, IDENTIFIER (enum value), OPEN_SQUARE (empty list) or OPEN_CURLY (empty map) token.
+	Token Token
+	// Value is the unquoted value of a string literal, 'true', 'false', '[]', '{}' or the lexeme of any other token.
+	Value string
+	// Range includes the closing bracket of empty lists and maps.
+	Range Range
+}
+
+func (l Literal) String() string {
+	switch l.Token.Type {
+	case STRING_LITERAL:
+		return strconv.Quote(l.Value)
+	default:
+		return l.Value
+	}
+}
+
+type typeLiteral struct {
+	literal      Literal
+	propertyType Token
+}
+
+func (p *parser) literal() (*Literal, error) {
+	// 'true' and 'false' are boolean literals, all other contextual keywords are names of enum values.
+	if t := p.peek().Type; t != TRUE && t != FALSE {
+		p.contextualName()
+	}
+	if !p.match(STRING_LITERAL, NUMBER, TRUE, FALSE, IDENTIFIER, OPEN_SQUARE, OPEN_CURLY) {
+		return nil, p.newError(CodeSyntax, "Expect literal value.", true)
+	}
+	token := p.previous()
+
+	literal := &Literal{
+		Token: token,
+		Value: token.Lexeme,
+	}
+
+	switch token.Type {
+	case STRING_LITERAL:
+		value, err := strconv.Unquote(token.Lexeme)
+		if err != nil {
+			return nil, p.newErrorAt(CodeInvalidValue, "Invalid string literal.", token, true)
+		}
+		literal.Value = value
+	case OPEN_SQUARE:
+		if !p.match(CLOSE_SQUARE) {
+			return nil, p.newError(CodeSyntax, "Expect ']' after '['. Only empty lists are supported as literals.", true)
+		}
+		literal.Value = "[]"
+	case OPEN_CURLY:
+		if !p.match(CLOSE_CURLY) {
+			return nil, p.newError(CodeSyntax, "Expect '}' after '{'. Only empty maps are supported as literals.", true)
+		}
+		literal.Value = "{}"
+	}
+	literal.Range = p.rangeFrom(token.Position())
+
+	return literal, nil
+}
+
+// checkLiteral reports an error if literal is not a valid value of propertyType.
+// Values of user defined types are checked after all declarations are parsed.
+func (p *parser) checkLiteral(literal *Literal, propertyType *PropertyType) error {
+	t := literal.Token.Type
+	valid := false
+	switch propertyType.Token.Type {
+	case STRING:
+		valid = t == STRING_LITERAL
+	case BOOL:
+		valid = t == TRUE || t == FALSE
+	case INT32:
+		_, err := strconv.ParseInt(literal.Value, 10, 32)
+		valid = t == NUMBER && err == nil
+	case INT64:
+		_, err := strconv.ParseInt(literal.Value, 10, 64)
+		valid = t == NUMBER && err == nil
+	case UINT32:
+		_, err := strconv.ParseUint(literal.Value, 10, 32)
+		valid = t == NUMBER && err == nil
+	case UINT64:
+		_, err := strconv.ParseUint(literal.Value, 10, 64)
+		valid = t == NUMBER && err == nil
+	case FLOAT32, FLOAT64:
+		valid = t == NUMBER
+	case LIST:
+		valid = t == OPEN_SQUARE
+	case MAP:
+		valid = t == OPEN_CURLY
+	case IDENTIFIER:
+		p.typeLiterals = append(p.typeLiterals, typeLiteral{
+			literal:      *literal,
+			propertyType: propertyType.Token,
+		})
+		return nil
+	}
+
+	if !valid {
+		return p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Invalid value for type '%s'.", propertyType.Token.Lexeme), literal.Token, true)
+	}
+	return nil
+}
+
+// checkTypeLiterals checks all literals of user defined types. Aliases are resolved to their underlying type.
+func (p *parser) checkTypeLiterals() {
+	objects := make(map[string]Object)
+	for _, o := range p.objects {
+		if o.Type == ENUM || o.Type == ALIAS {
+			objects[o.Name.Lexeme] = o
+		}
+	}
+
+	for _, l := range p.typeLiterals {
+		name := l.propertyType.Lexeme
+		object, ok := objects[name]
+		visited := make(map[string]struct{})
+		for ok && object.Type == ALIAS {
+			if _, ok := visited[object.Name.Lexeme]; ok {
+				// alias cycles are reported by the declaration cycle detector
+				break
+			}
+			visited[object.Name.Lexeme] = struct{}{}
+
+			if object.ValueType.Token.Type != IDENTIFIER {
+				err := p.checkLiteral(&l.literal, object.ValueType)
+				if err != nil {
+					p.errors = append(p.errors, p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Invalid value for type '%s'.", l.propertyType.Lexeme), l.literal.Token, true))
+				}
+				break
+			}
+			name = object.ValueType.Token.Lexeme
+			object, ok = objects[name]
+		}
+		if ok && object.Type == ALIAS {
+			continue
+		}
+
+		if !ok {
+			if _, ok := p.types[name]; ok {
+				p.errors = append(p.errors, p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Invalid value for type '%s'.", l.propertyType.Lexeme), l.literal.Token, true))
+			}
+			continue
+		}
+
+		if l.literal.Token.Type != IDENTIFIER {
+			p.errors = append(p.errors, p.newErrorAt(CodeInvalidValue, fmt.Sprintf("Invalid value for type '%s'.", l.propertyType.Lexeme), l.literal.Token, true))
+			continue
+		}
+
+		found := false
+		for _, v := range object.Properties {
+			if v.Name == l.literal.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.errors = append(p.errors, p.newErrorAt(CodeUndefinedEnumValue, fmt.Sprintf("Enum '%s' has no value '%s'.", object.Name.Lexeme, l.literal.Value), l.literal.Token, true))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cge
+
+import "fmt"
+
+// isMapKeyType reports whether tokenType can be used as the key type of a map. Enums are allowed as well.
+func isMapKeyType(tokenType TokenType) bool {
+	switch tokenType {
+	case STRING, INT32, INT64, UINT32, UINT64:
+		return true
+	}
+	return false
+}
+
+// checkMapKeys checks that all user defined map key types are enums or aliases of valid key types.
+func (p *parser) checkMapKeys() {
+	objects := make(map[string]Object)
+	for _, o := range p.objects {
+		if o.Type == TYPE || o.Type == ENUM || o.Type == UNION || o.Type == ALIAS {
+			objects[o.Name.Lexeme] = o
+		}
+	}
+
+	for _, key := range p.mapKeys {
+		keyType := key
+		object, ok := objects[keyType.Token.Lexeme]
+		visited := make(map[string]struct{})
+		for ok && object.Type == ALIAS {
+			if _, ok := visited[object.Name.Lexeme]; ok {
+				// alias cycles are reported by the declaration cycle detector
+				break
+			}
+			visited[object.Name.Lexeme] = struct{}{}
+			keyType = object.ValueType
+			object, ok = objects[keyType.Token.Lexeme]
+		}
+		if keyType.Token.Type == IDENTIFIER && (!ok || object.Type == ENUM || object.Type == ALIAS) {
+			continue
+		}
+		if keyType.Token.Type != IDENTIFIER && isMapKeyType(keyType.Token.Type) {
+			continue
+		}
+		p.errors = append(p.errors, p.newErrorAt(CodeInvalidMapKey, fmt.Sprintf("Invalid map key type '%s'. Only strings, integers and enums are allowed.", key.Token.Lexeme), key.Token, true))
+	}
+}
@@ -5,23 +5,48 @@ import (
 	"io"
 	"strconv"
 	"strings"
-
-	"github.com/Bananenpro/cli"
 )
 
 type Metadata struct {
 	Name       string
 	Comments   []string
 	CGEVersion string
+	// NameToken and VersionToken are the IDENTIFIER and VERSION_NUMBER tokens of the name and version declarations.
+	NameToken     Token
+	VersionToken  Token
+	CommentTokens []Token
 }
 
-type ObjectType string
-
 type Object struct {
-	Comments   []string
-	Type       TokenType
+	Comments []string
+	// CommentTokens are the COMMENT tokens of Comments.
+	CommentTokens []Token
+	Type          TokenType
+	// Name is the IDENTIFIER token of the name or the CONFIG token of a config declaration.
 	Name       Token
 	Properties []Property
+	// Discriminator is the name of the property which contains the name of the actual type of a union.
+	Discriminator Token
+	// ValueType is the type of a constant, the underlying type of an alias or the type of the values of an enum (STRING, INT32 or INT64).
+	// It is nil for enums without an explicit type.
+	ValueType *PropertyType
+	// Value is the value of a constant.
+	Value *Literal
+	// Extends is the base type of a type, event or command. It is nil if the object doesn't extend another type.
+	Extends *PropertyType
+	// TypeParameters are the type parameters of a generic type. It is empty for all other objects.
+	TypeParameters []Token
+	// Responses are the events which can be sent in response to a command. It is empty for all other objects.
+	Responses []Token
+	// Scope is the audience of an event (one of EventScopes). Its lexeme is empty if no scope was specified.
+	Scope      Token
+	Attributes []Attribute
+	// Deprecated is nil if the object is not deprecated.
+	Deprecated *Deprecation
+	// Implicit is true for the empty config object which is added if no config object is declared.
+	Implicit bool
+	// Range starts at the first attribute or keyword of the declaration. It doesn't include the leading comments.
+	Range Range
 }
 
 func (o Object) String() string {
@@ -29,6 +54,12 @@ func (o Object) String() string {
 	for _, c := range o.Comments {
 		text = fmt.Sprintf("%s// %s\n", text, c)
 	}
+	if o.Type == CONST {
+		return fmt.Sprintf("%s%s: %s = %s", text, o.Name.Lexeme, o.ValueType.Token.Lexeme, o.Value)
+	}
+	if o.Type == ALIAS {
+		return fmt.Sprintf("%s%s = %s", text, o.Name.Lexeme, o.ValueType.Token.Lexeme)
+	}
 	text = fmt.Sprintf("%s%s {", text, o.Name.Lexeme)
 
 	for _, p := range o.Properties {
@@ -39,204 +70,405 @@ func (o Object) String() string {
 }
 
 type Property struct {
-	Comments []string
-	Name     string
-	Type     *PropertyType
+	Comments      []string
+	CommentTokens []Token
+	Name          string
+	// NameToken is the IDENTIFIER token of Name.
+	NameToken Token
+	Type      *PropertyType
+	Optional  bool
+	Default   *Literal
+	// Value is the explicit value of an enum value.
+	Value *Literal
+	// Constraints restrict the valid values of the property.
+	Constraints []Constraint
+	Attributes  []Attribute
+	// Deprecated is nil if the property or enum value is not deprecated.
+	Deprecated *Deprecation
+	// Range starts at the first attribute or the name of the property. It doesn't include the leading comments.
+	Range Range
 }
 
 type PropertyType struct {
-	Token   Token
+	Token Token
+	// Generic is the element type of a list or the value type of a map.
 	Generic *PropertyType
+	// Key is the key type of a map. It is nil for maps with string keys which don't specify a key type ('map<T>').
+	Key *PropertyType
+	// TypeArguments are the type arguments of an instantiation of a generic type.
+	TypeArguments []*PropertyType
+	// Range includes the generic type and type arguments. It covers the whole declaration of inline types and enums.
+	Range Range
 }
 
 func (o Property) String() string {
-	return fmt.Sprintf("%s: %s", o.Name, o.Type.Token.Lexeme)
+	typeName := o.Type.Token.Lexeme
+	if len(o.Constraints) > 0 {
+		constraints := make([]string, len(o.Constraints))
+		for i, c := range o.Constraints {
+			constraints[i] = c.String()
+		}
+		typeName = fmt.Sprintf("%s [%s]", typeName, strings.Join(constraints, ", "))
+	}
+	if o.Optional {
+		return fmt.Sprintf("%s?: %s", o.Name, typeName)
+	}
+	if o.Default != nil {
+		return fmt.Sprintf("%s: %s = %s", o.Name, typeName, o.Default)
+	}
+	return fmt.Sprintf("%s: %s", o.Name, typeName)
 }
 
 type parser struct {
 	tokens                  []Token
 	current                 int
 	lines                   [][]rune
-	commands                map[string]struct{}
-	events                  map[string]struct{}
-	types                   map[string]struct{}
+	filename                string
+	sources                 map[string][][]rune
+	imports                 *importState
+	commands                map[string]Token
+	events                  map[string]Token
+	types                   map[string]Token
+	constants               map[string]Token
 	config                  bool
 	accessedTypeIdentifiers []Token
+	typeLiterals            []typeLiteral
+	mapKeys                 []*PropertyType
+	typeReferences          []*PropertyType
+	typeParameters          []Token
 	objects                 []Object
 	errors                  []error
+	warnings                []Diagnostic
 	cgeVersion              string
+	// skipImports disables the resolution of imports. It is used to check the syntax of a single file.
+	skipImports bool
 }
 
+// LatestVersion is the newest version of the CGE language which is supported by the parser.
+const LatestVersion = "0.4"
+
+// Parse parses a CGE file. Relative imports are resolved relative to the current working directory.
+// Warnings are not reported. Use ParseAST to receive them.
 func Parse(source io.Reader, cgeVersion string) (Metadata, []Object, []error) {
-	tokens, lines, err := scan(source)
+	return ParseFile(source, "", cgeVersion)
+}
+
+// ParseFile parses a CGE file like Parse. filename is used to resolve relative imports and is included in error messages.
+// It can be a local path or an http(s) URL.
+func ParseFile(source io.Reader, filename, cgeVersion string) (Metadata, []Object, []error) {
+	file, errs := ParseAST(source, filename, cgeVersion)
+	if file == nil {
+		return Metadata{}, nil, errs
+	}
+	return file.Metadata, file.Objects, errs
+}
+
+// ParseAST parses a CGE file like ParseFile and returns its syntax tree.
+// The returned file is nil if the metadata at the start of the file could not be parsed.
+func ParseAST(source io.Reader, filename, cgeVersion string) (*File, []error) {
+	tokens, lines, scanErrs, err := scan(source, filename)
 	if err != nil {
-		return Metadata{}, nil, []error{err}
+		return nil, []error{err}
 	}
 
 	parser := &parser{
 		tokens:                  tokens,
 		lines:                   lines,
-		commands:                make(map[string]struct{}),
-		events:                  make(map[string]struct{}),
-		types:                   make(map[string]struct{}),
+		filename:                filename,
+		sources:                 map[string][][]rune{filename: lines},
+		imports:                 newImportState(filename),
+		commands:                make(map[string]Token),
+		events:                  make(map[string]Token),
+		types:                   make(map[string]Token),
+		constants:               make(map[string]Token),
 		accessedTypeIdentifiers: make([]Token, 0),
 		objects:                 make([]Object, 0),
-		errors:                  make([]error, 0),
+		errors:                  append(make([]error, 0), scanErrs...),
 		cgeVersion:              cgeVersion,
 	}
 
-	return parser.parse()
+	file, errs := parser.parse()
+	return file, withoutErrorTokens(errs)
 }
 
-func (p *parser) parse() (Metadata, []Object, []error) {
+func (p *parser) parse() (*File, []error) {
 	name, comments, err := p.name()
 	if err != nil {
-		return Metadata{}, nil, []error{err}
+		return nil, append(p.errors, err)
 	}
 	version, err := p.version()
 	if err != nil {
-		return Metadata{}, nil, []error{err}
+		return nil, append(p.errors, err)
 	}
-	if !isVersionCompatible(version, p.cgeVersion) {
-		cli.Warn("CGE version mismatch! Input file: v%s, cg-gen-events: v%s. There might be parsing issues.", version, p.cgeVersion)
+	if !isVersionCompatible(version.Lexeme, p.cgeVersion) {
+		p.warnAt(CodeVersionMismatch, fmt.Sprintf("CGE version mismatch! Input file: v%s, cg-gen-events: v%s. There might be parsing issues.", version.Lexeme, p.cgeVersion), version)
 	}
 
-	for p.peek().Type != EOF {
-		decl, err := p.declaration()
-		if err != nil {
-			p.errors = append(p.errors, err)
-			if e, ok := err.(ParseError); ok {
-				p.skipBlock(e.inBlock)
-			}
-			continue
-		}
-		p.objects = append(p.objects, decl)
-	}
+	p.declarations()
 
 	for _, id := range p.accessedTypeIdentifiers {
 		if _, ok := p.types[id.Lexeme]; !ok {
-			p.errors = append(p.errors, p.newErrorAt(fmt.Sprintf("Undefined type '%s'.", id.Lexeme), id, true))
+			p.errors = append(p.errors, p.newErrorAt(CodeUndefinedType, fmt.Sprintf("Undefined type '%s'.", id.Lexeme), id, true))
 		}
 	}
 
+	p.checkTypeLiterals()
+	p.checkMapKeys()
+	p.checkTypeArguments()
+	p.checkUnions()
+	p.checkExtends()
+	p.checkResponses()
+
 	if !p.config {
 		p.objects = append(p.objects, Object{
-			Type: CONFIG,
+			Type:     CONFIG,
+			Implicit: true,
 		})
 	}
 
 	p.detectDeclarationCycles()
 
-	return Metadata{
-		Name:       name,
-		CGEVersion: version,
-		Comments:   comments,
-	}, p.objects, p.errors
+	return &File{
+		Metadata: Metadata{
+			Name:          name.Lexeme,
+			CGEVersion:    version.Lexeme,
+			Comments:      commentLexemes(comments),
+			NameToken:     name,
+			VersionToken:  version,
+			CommentTokens: comments,
+		},
+		Objects:  p.objects,
+		Warnings: p.warnings,
+		Range: Range{
+			Start: Position{File: p.filename},
+			End:   p.tokens[len(p.tokens)-1].Position(),
+		},
+	}, p.errors
+}
+
+func (p *parser) declarations() {
+	for p.peek().Type != EOF {
+		if p.match(IMPORT) {
+			err := p.importDeclaration()
+			if err != nil {
+				p.errors = append(p.errors, err)
+			}
+			continue
+		}
+
+		if p.peekPastComments().Type == CONST || p.isAlias() {
+			var decl Object
+			var err error
+			if p.peekPastComments().Type == CONST {
+				decl, err = p.constant()
+			} else {
+				decl, err = p.alias()
+			}
+			if err != nil {
+				p.errors = append(p.errors, err)
+				p.skipDeclaration()
+				continue
+			}
+			p.objects = append(p.objects, decl)
+			continue
+		}
+
+		decl, err := p.declaration()
+		if err != nil {
+			p.errors = append(p.errors, err)
+			if e, ok := err.(ParseError); ok {
+				p.skipBlock(e.inBlock)
+			}
+			continue
+		}
+		p.objects = append(p.objects, decl)
+	}
 }
 
-func (p *parser) name() (string, []string, error) {
-	var comments []string
+func (p *parser) name() (Token, []Token, error) {
+	var comments []Token
 	for p.match(COMMENT) {
-		comments = append(comments, p.previous().Lexeme)
+		comments = append(comments, p.previous())
 	}
 
 	if !p.match(NAME) {
-		return "", nil, p.newError("Expect 'name' token.", false)
+		return Token{}, nil, p.newError(CodeSyntax, "Expect 'name' token.", false)
 	}
 
 	if !p.match(IDENTIFIER) {
-		return "", nil, p.newError("Expect name of game.", false)
+		return Token{}, nil, p.newError(CodeSyntax, "Expect name of game.", false)
 	}
 
-	return p.previous().Lexeme, comments, nil
+	return p.previous(), comments, nil
 }
 
-func (p *parser) version() (string, error) {
+func (p *parser) version() (Token, error) {
 	if !p.match(VERSION) {
-		return "", p.newError("Expect 'version' token.", false)
+		return Token{}, p.newError(CodeSyntax, "Expect 'version' token.", false)
 	}
 
 	if !p.match(VERSION_NUMBER) {
-		return "", p.newError("Expect CGE version.", false)
+		return Token{}, p.newError(CodeSyntax, "Expect CGE version.", false)
 	}
 
-	return p.previous().Lexeme, nil
+	return p.previous(), nil
 }
 
 func (p *parser) declaration() (Object, error) {
-	var comments []string
-	for p.match(COMMENT) {
-		comments = append(comments, p.previous().Lexeme)
+	comments, attributes, deprecation, err := p.prefix(false)
+	if err != nil {
+		return Object{}, err
 	}
+	start := startOf(attributes, p.peek())
 
-	if !p.match(CONFIG, COMMAND, EVENT, TYPE, ENUM) {
-		return Object{}, p.newError("Expect config, command, event, type or enum declaration.", false)
+	if !p.match(CONFIG, COMMAND, EVENT, TYPE, ENUM, UNION) {
+		return Object{}, p.newError(CodeSyntax, "Expect import, const, config, command, event, type, enum or union declaration.", false)
 	}
 
 	objectType := p.previous().Type
 
+	var scope Token
+	if objectType == EVENT && p.match(OPEN_PAREN) {
+		scope, err = p.eventScope()
+		if err != nil {
+			return Object{}, err
+		}
+	} else if p.peek().Type == OPEN_PAREN {
+		return Object{}, p.newError(CodeInvalidScope, "Only events can have a scope.", false)
+	}
+
 	if objectType == CONFIG {
 		if p.config {
-			return Object{}, p.newErrorAt("Only one config object is allowed.", p.previous(), false)
+			return Object{}, p.newErrorAt(CodeRedeclaration, "Only one config object is allowed.", p.previous(), false)
 		}
 		p.config = true
 	} else {
 		if !p.match(IDENTIFIER) {
-			return Object{}, p.newError(fmt.Sprintf("Expect identifier after '%s' keyword.", strings.ToLower(string(objectType))), false)
+			return Object{}, p.newError(CodeSyntax, fmt.Sprintf("Expect identifier after '%s' keyword.", strings.ToLower(string(objectType))), false)
 		}
 	}
 	name := p.previous()
 
 	switch objectType {
 	case COMMAND:
-		if _, ok := p.commands[name.Lexeme]; ok {
-			return Object{}, p.newErrorAt(fmt.Sprintf("Command '%s' already defined.", name.Lexeme), name, false)
+		if previous, ok := p.commands[name.Lexeme]; ok {
+			return Object{}, p.newRedeclarationError(fmt.Sprintf("Command '%s' already defined.", name.Lexeme), name, previous, false)
 		}
-		p.commands[name.Lexeme] = struct{}{}
+		p.commands[name.Lexeme] = name
 	case EVENT:
-		if _, ok := p.events[name.Lexeme]; ok {
-			return Object{}, p.newErrorAt(fmt.Sprintf("Event '%s' already defined.", name.Lexeme), name, false)
+		if previous, ok := p.events[name.Lexeme]; ok {
+			return Object{}, p.newRedeclarationError(fmt.Sprintf("Event '%s' already defined.", name.Lexeme), name, previous, false)
+		}
+		p.events[name.Lexeme] = name
+	case TYPE, ENUM, UNION:
+		if previous, ok := p.types[name.Lexeme]; ok {
+			return Object{}, p.newRedeclarationError(fmt.Sprintf("Type '%s' already defined.", name.Lexeme), name, previous, false)
+		}
+		p.types[name.Lexeme] = name
+	}
+
+	var typeParameters []Token
+	if objectType == TYPE && p.match(LESS) {
+		typeParameters, err = p.typeParameterList()
+		if err != nil {
+			return Object{}, err
+		}
+	}
+
+	var extends *PropertyType
+	if (objectType == TYPE || objectType == EVENT || objectType == COMMAND) && p.match(EXTENDS) {
+		if !p.match(IDENTIFIER) {
+			return Object{}, p.newError(CodeSyntax, "Expect type name after 'extends' keyword.", false)
+		}
+		extends = &PropertyType{
+			Token: p.previous(),
+			Range: p.previous().Range(),
+		}
+		p.accessedTypeIdentifiers = append(p.accessedTypeIdentifiers, extends.Token)
+	}
+
+	var responses []Token
+	if objectType == COMMAND && p.match(ARROW) {
+		responses, err = p.responses()
+		if err != nil {
+			return Object{}, err
+		}
+	} else if p.peek().Type == ARROW {
+		return Object{}, p.newError(CodeInvalidResponse, "Only commands can have responses.", false)
+	}
+
+	var valueType *PropertyType
+	if objectType == ENUM {
+		var err error
+		valueType, err = p.enumType()
+		if err != nil {
+			return Object{}, err
+		}
+	}
+
+	var discriminator Token
+	if objectType == UNION {
+		if !p.match(OPEN_PAREN) {
+			return Object{}, p.newError(CodeSyntax, "Expect '(' after union name.", false)
+		}
+		if !p.matchName() {
+			return Object{}, p.newError(CodeSyntax, "Expect discriminator property name.", false)
 		}
-		p.events[name.Lexeme] = struct{}{}
-	case TYPE, ENUM:
-		if _, ok := p.types[name.Lexeme]; ok {
-			return Object{}, p.newErrorAt(fmt.Sprintf("Type '%s' already defined.", name.Lexeme), name, false)
+		discriminator = p.previous()
+		if !p.match(CLOSE_PAREN) {
+			return Object{}, p.newError(CodeSyntax, "Expect ')' after discriminator.", false)
 		}
-		p.types[name.Lexeme] = struct{}{}
 	}
 
 	if !p.match(OPEN_CURLY) {
 		if objectType == CONFIG {
-			return Object{}, p.newError(fmt.Sprintf("Expect block after %s keyword.", strings.ToLower(string(objectType))), true)
+			return Object{}, p.newError(CodeSyntax, fmt.Sprintf("Expect block after %s keyword.", strings.ToLower(string(objectType))), true)
 		} else {
-			return Object{}, p.newError(fmt.Sprintf("Expect block after %s name.", strings.ToLower(string(objectType))), true)
+			return Object{}, p.newError(CodeSyntax, fmt.Sprintf("Expect block after %s name.", strings.ToLower(string(objectType))), true)
 		}
 	}
 
 	var properties []Property
-	var err error
 	if objectType == ENUM {
-		properties, err = p.enumBlock()
+		var names []Token
+		properties, names, err = p.enumBlock()
+		if err == nil {
+			p.checkEnumValues(valueType, properties, names)
+		}
+	} else if objectType == UNION {
+		properties, err = p.unionBlock()
 	} else {
-		properties, err = p.block()
+		p.typeParameters = typeParameters
+		properties, err = p.block(objectType == CONFIG || objectType == COMMAND)
+		p.typeParameters = nil
 	}
 	if err != nil {
 		return Object{}, err
 	}
 
 	return Object{
-		Comments:   comments,
-		Type:       objectType,
-		Name:       name,
-		Properties: properties,
+		Comments:       commentLexemes(comments),
+		CommentTokens:  comments,
+		Type:           objectType,
+		Name:           name,
+		Properties:     properties,
+		Discriminator:  discriminator,
+		ValueType:      valueType,
+		Extends:        extends,
+		TypeParameters: typeParameters,
+		Responses:      responses,
+		Scope:          scope,
+		Attributes:     attributes,
+		Deprecated:     deprecation,
+		Range:          p.rangeFrom(start),
 	}, nil
 }
 
-func (p *parser) block() ([]Property, error) {
+func (p *parser) block(allowDefaults bool) ([]Property, error) {
 	properties := make([]Property, 0)
 
 	for p.peek().Type != EOF && p.peek().Type != CLOSE_CURLY {
-		property, err := p.property()
+		property, err := p.property(allowDefaults)
 		if err != nil {
 			p.errors = append(p.errors, err)
 			p.skipProperty()
@@ -249,48 +481,53 @@ func (p *parser) block() ([]Property, error) {
 	}
 
 	if !p.match(CLOSE_CURLY) {
-		return nil, p.newError("Expect '}' after block.", true)
+		return nil, p.newError(CodeSyntax, "Expect '}' after block.", true)
 	}
 
 	return properties, nil
 }
 
-func (p *parser) enumBlock() ([]Property, error) {
+func (p *parser) enumBlock() ([]Property, []Token, error) {
 	properties := make([]Property, 0)
+	names := make([]Token, 0)
 
 	for p.peek().Type != EOF && p.peek().Type != CLOSE_CURLY {
-		property, err := p.enumValue()
+		property, name, err := p.enumValue()
 		if err != nil {
 			p.errors = append(p.errors, err)
 			p.skipProperty()
 			continue
 		}
 		properties = append(properties, property)
+		names = append(names, name)
 		if !p.match(COMMA) {
 			break
 		}
 	}
 
 	if !p.match(CLOSE_CURLY) {
-		return nil, p.newError("Expect '}' after block.", true)
+		return nil, nil, p.newError(CodeSyntax, "Expect '}' after block.", true)
 	}
 
-	return properties, nil
+	return properties, names, nil
 }
 
-func (p *parser) property() (Property, error) {
-	var comments []string
-	for p.match(COMMENT) {
-		comments = append(comments, p.previous().Lexeme)
+func (p *parser) property(allowDefaults bool) (Property, error) {
+	comments, attributes, deprecation, err := p.prefix(true)
+	if err != nil {
+		return Property{}, err
 	}
 
-	if !p.match(IDENTIFIER) {
-		return Property{}, p.newError("Expect property name.", true)
+	if !p.matchName() {
+		return Property{}, p.newError(CodeSyntax, "Expect property name.", true)
 	}
 	name := p.previous()
+	start := startOf(attributes, name)
+
+	optional := p.match(QUESTION)
 
 	if !p.match(COLON) {
-		return Property{}, p.newError("Expect ':' after property name.", true)
+		return Property{}, p.newError(CodeSyntax, "Expect ':' after property name.", true)
 	}
 
 	propertyType, err := p.propertyType()
@@ -298,62 +535,145 @@ func (p *parser) property() (Property, error) {
 		return Property{}, err
 	}
 
+	var constraints []Constraint
+	if p.match(OPEN_SQUARE) {
+		constraints, err = p.constraints(propertyType)
+		if err != nil {
+			return Property{}, err
+		}
+	}
+
+	var defaultValue *Literal
+	if p.match(EQUAL) {
+		equal := p.previous()
+		defaultValue, err = p.literal()
+		if err != nil {
+			return Property{}, err
+		}
+		if !allowDefaults {
+			return Property{}, p.newErrorAt(CodeInvalidDefault, "Default values are only allowed in config and command properties.", equal, true)
+		}
+		if optional {
+			return Property{}, p.newErrorAt(CodeInvalidDefault, "Optional properties cannot have a default value.", equal, true)
+		}
+		err = p.checkLiteral(defaultValue, propertyType)
+		if err != nil {
+			return Property{}, err
+		}
+	}
+
 	return Property{
-		Comments: comments,
-		Name:     name.Lexeme,
-		Type:     propertyType,
+		Comments:      commentLexemes(comments),
+		CommentTokens: comments,
+		Name:          name.Lexeme,
+		NameToken:     name,
+		Type:          propertyType,
+		Optional:      optional,
+		Default:       defaultValue,
+		Constraints:   constraints,
+		Attributes:    attributes,
+		Deprecated:    deprecation,
+		Range:         p.rangeFrom(start),
 	}, nil
 }
 
-func (p *parser) enumValue() (Property, error) {
-	var comments []string
-	for p.match(COMMENT) {
-		comments = append(comments, p.previous().Lexeme)
+func (p *parser) enumValue() (Property, Token, error) {
+	comments, attributes, deprecation, err := p.prefix(true)
+	if err != nil {
+		return Property{}, Token{}, err
 	}
 
-	if !p.match(IDENTIFIER) {
-		return Property{}, p.newError("Expect property name.", true)
+	if !p.matchName() {
+		return Property{}, Token{}, p.newError(CodeSyntax, "Expect property name.", true)
 	}
 	name := p.previous()
+	start := startOf(attributes, name)
+
+	var value *Literal
+	if p.match(EQUAL) {
+		value, err = p.literal()
+		if err != nil {
+			return Property{}, Token{}, err
+		}
+	}
 
 	return Property{
-		Comments: comments,
-		Name:     name.Lexeme,
-	}, nil
+		Comments:      commentLexemes(comments),
+		CommentTokens: comments,
+		Name:          name.Lexeme,
+		NameToken:     name,
+		Value:         value,
+		Attributes:    attributes,
+		Deprecated:    deprecation,
+		Range:         p.rangeFrom(start),
+	}, name, nil
 }
 
 func (p *parser) propertyType() (*PropertyType, error) {
-	if !p.match(STRING, BOOL, INT32, INT64, FLOAT32, FLOAT64, MAP, LIST, IDENTIFIER, TYPE, ENUM) {
-		return &PropertyType{}, p.newError("Expect type after property name.", true)
+	if !p.match(STRING, BOOL, INT32, INT64, FLOAT32, FLOAT64, UINT32, UINT64, BYTES, TIMESTAMP, DURATION, UUID, MAP, LIST, IDENTIFIER, TYPE, ENUM) {
+		return &PropertyType{}, p.newError(CodeSyntax, "Expect type after property name.", true)
 	}
 
 	propertyType := p.previous()
+	start := propertyType.Position()
 	var generic *PropertyType
+	var key *PropertyType
+	var typeArguments []*PropertyType
 
-	if propertyType.Type == IDENTIFIER {
+	if propertyType.Type == IDENTIFIER && p.isTypeParameter(propertyType.Lexeme) {
+		propertyType.Type = TYPE_PARAMETER
+		if p.peek().Type == LESS {
+			return &PropertyType{}, p.newError(CodeTypeArguments, fmt.Sprintf("Type parameter '%s' cannot have type arguments.", propertyType.Lexeme), true)
+		}
+	} else if propertyType.Type == IDENTIFIER {
 		p.accessedTypeIdentifiers = append(p.accessedTypeIdentifiers, propertyType)
+		if p.match(LESS) {
+			var err error
+			typeArguments, err = p.typeArgumentList()
+			if err != nil {
+				return &PropertyType{}, err
+			}
+		}
 	} else if propertyType.Type == TYPE || propertyType.Type == ENUM {
 		if !p.match(IDENTIFIER) {
-			return &PropertyType{}, p.newError(fmt.Sprintf("Expect identifier after 'type' keyword."), true)
+			return &PropertyType{}, p.newError(CodeSyntax, fmt.Sprintf("Expect identifier after 'type' keyword."), true)
 		}
 
 		identifier := p.previous()
-		if _, ok := p.types[identifier.Lexeme]; ok {
-			return &PropertyType{}, p.newErrorAt(fmt.Sprintf("Type '%s' already defined.", identifier.Lexeme), identifier, true)
+		if previous, ok := p.types[identifier.Lexeme]; ok {
+			return &PropertyType{}, p.newRedeclarationError(fmt.Sprintf("Type '%s' already defined.", identifier.Lexeme), identifier, previous, true)
+		}
+		p.types[identifier.Lexeme] = identifier
+
+		var valueType *PropertyType
+		if propertyType.Type == ENUM {
+			var err error
+			valueType, err = p.enumType()
+			if err != nil {
+				return &PropertyType{}, err
+			}
 		}
-		p.types[identifier.Lexeme] = struct{}{}
 
 		if !p.match(OPEN_CURLY) {
-			return &PropertyType{}, p.newError("Expect block after type name.", true)
+			return &PropertyType{}, p.newError(CodeSyntax, "Expect block after type name.", true)
 		}
 
+		// type parameters of the enclosing generic type are not visible in nested declarations
+		typeParameters := p.typeParameters
+		p.typeParameters = nil
+
 		var properties []Property
 		var err error
 		if propertyType.Type == TYPE {
-			properties, err = p.block()
+			properties, err = p.block(false)
 		} else {
-			properties, err = p.enumBlock()
+			var names []Token
+			properties, names, err = p.enumBlock()
+			if err == nil {
+				p.checkEnumValues(valueType, properties, names)
+			}
 		}
+		p.typeParameters = typeParameters
 		if err != nil {
 			return &PropertyType{}, err
 		}
@@ -362,12 +682,14 @@ func (p *parser) propertyType() (*PropertyType, error) {
 			Type:       propertyType.Type,
 			Name:       identifier,
 			Properties: properties,
+			ValueType:  valueType,
+			Range:      p.rangeFrom(start),
 		})
 
 		propertyType = identifier
 	} else if propertyType.Type == MAP || propertyType.Type == LIST {
 		if !p.match(LESS) {
-			return &PropertyType{}, p.newError("Expect generic.", true)
+			return &PropertyType{}, p.newError(CodeSyntax, "Expect generic.", true)
 		}
 
 		var err error
@@ -376,15 +698,36 @@ func (p *parser) propertyType() (*PropertyType, error) {
 			return &PropertyType{}, err
 		}
 
+		if propertyType.Type == MAP && p.match(COMMA) {
+			key = generic
+			if key.Token.Type == IDENTIFIER {
+				p.mapKeys = append(p.mapKeys, key)
+			} else if !isMapKeyType(key.Token.Type) {
+				return &PropertyType{}, p.newErrorAt(CodeInvalidMapKey, fmt.Sprintf("Invalid map key type '%s'. Only strings, integers and enums are allowed.", key.Token.Lexeme), key.Token, true)
+			}
+
+			generic, err = p.propertyType()
+			if err != nil {
+				return &PropertyType{}, err
+			}
+		}
+
 		if !p.match(GREATER) {
-			return &PropertyType{}, p.newError("Expect '>' after generic value.", true)
+			return &PropertyType{}, p.newError(CodeSyntax, "Expect '>' after generic value.", true)
 		}
 	}
 
-	return &PropertyType{
-		Token:   propertyType,
-		Generic: generic,
-	}, nil
+	result := &PropertyType{
+		Token:         propertyType,
+		Generic:       generic,
+		Key:           key,
+		TypeArguments: typeArguments,
+		Range:         p.rangeFrom(start),
+	}
+	if propertyType.Type == IDENTIFIER {
+		p.typeReferences = append(p.typeReferences, result)
+	}
+	return result, nil
 }
 
 func (p *parser) match(types ...TokenType) bool {
@@ -397,6 +740,19 @@ func (p *parser) match(types ...TokenType) bool {
 	return false
 }
 
+// matchName matches an IDENTIFIER token or a contextual keyword, which is turned into an IDENTIFIER token.
+func (p *parser) matchName() bool {
+	p.contextualName()
+	return p.match(IDENTIFIER)
+}
+
+// contextualName turns the next token into an IDENTIFIER token if it is a contextual keyword.
+func (p *parser) contextualName() {
+	if _, ok := contextualKeywords[p.peek().Type]; ok {
+		p.tokens[p.current].Type = IDENTIFIER
+	}
+}
+
 func (p *parser) previous() Token {
 	return p.tokens[p.current-1]
 }
@@ -409,6 +765,21 @@ func (p *parser) peekNext() Token {
 	return p.tokens[p.current+1]
 }
 
+// peekPastComments returns the next token which is neither a comment nor part of an attribute.
+func (p *parser) peekPastComments() Token {
+	return p.tokens[p.skipPrefix(p.current)]
+}
+
+func (p *parser) skipDeclaration() {
+	for p.peek().Type != EOF {
+		switch p.peek().Type {
+		case IMPORT, CONST, CONFIG, COMMAND, EVENT, TYPE, ENUM, UNION, COMMENT, AT:
+			return
+		}
+		p.current++
+	}
+}
+
 func (p *parser) skipBlock(inBlock bool) {
 	if p.peek().Type == EOF {
 		return
@@ -444,6 +815,7 @@ func (p *parser) skipProperty() {
 	}
 
 	nestingLevel := 0
+	squareNestingLevel := 0
 	for p.peek().Type != EOF {
 		if p.peek().Type == OPEN_CURLY {
 			nestingLevel++
@@ -452,8 +824,12 @@ func (p *parser) skipProperty() {
 			if nestingLevel == -1 {
 				return
 			}
+		} else if p.peek().Type == OPEN_SQUARE {
+			squareNestingLevel++
+		} else if p.peek().Type == CLOSE_SQUARE && squareNestingLevel > 0 {
+			squareNestingLevel--
 		}
-		if p.peek().Type == COMMA && nestingLevel == 0 {
+		if p.peek().Type == COMMA && nestingLevel == 0 && squareNestingLevel == 0 {
 			p.current++
 			return
 		}
@@ -492,37 +868,78 @@ func isVersionCompatible(fileVersion, cgeVersion string) bool {
 
 type ParseError struct {
 	Token   Token
+	Code    Code
 	Message string
 	Line    []rune
+	// Related contains additional locations like the previous declaration of a name which is already defined.
+	Related []RelatedLocation
 	inBlock bool
 }
 
 func (p ParseError) Error() string {
-	return generateErrorText(p.Message, p.Line, p.Token.Line, p.Token.Column, p.Token.Column+len([]rune(p.Token.Lexeme)))
+	return p.Diagnostic().Error()
 }
 
-func (p *parser) newError(message string, inBlock bool) error {
+// Diagnostic converts the error into a diagnostic with the range of Token.
+func (p ParseError) Diagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Code:     p.Code,
+		Message:  p.Message,
+		Range:    p.Token.Range(),
+		Source:   string(p.Line),
+		Related:  p.Related,
+	}
+}
+
+// withoutErrorTokens removes all parse errors at ERROR tokens because the scanner has already reported them.
+func withoutErrorTokens(errs []error) []error {
+	result := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if e, ok := err.(ParseError); ok && e.Token.Type == ERROR {
+			continue
+		}
+		result = append(result, err)
+	}
+	return result
+}
+
+func (p *parser) newError(code Code, message string, inBlock bool) error {
 	line := []rune{}
 	if p.peek().Line >= 0 {
 		line = p.lines[p.peek().Line]
 	}
 	return ParseError{
 		Token:   p.peek(),
+		Code:    code,
 		Message: message,
 		Line:    line,
 		inBlock: inBlock,
 	}
 }
 
-func (p *parser) newErrorAt(message string, token Token, inBlock bool) error {
+func (p *parser) newErrorAt(code Code, message string, token Token, inBlock bool) error {
 	line := []rune{}
-	if token.Line >= 0 {
-		line = p.lines[token.Line]
+	if lines, ok := p.sources[token.File]; ok && token.Line >= 0 {
+		line = lines[token.Line]
 	}
 	return ParseError{
 		Token:   token,
+		Code:    code,
 		Message: message,
 		Line:    line,
 		inBlock: inBlock,
 	}
 }
+
+// newRedeclarationError returns an error at name which refers to the previous declaration of name.
+func (p *parser) newRedeclarationError(message string, name, previous Token, inBlock bool) error {
+	err := p.newErrorAt(CodeRedeclaration, message, name, inBlock).(ParseError)
+	err.Related = []RelatedLocation{
+		{
+			Message: fmt.Sprintf("'%s' is first defined here.", previous.Lexeme),
+			Range:   previous.Range(),
+		},
+	}
+	return err
+}
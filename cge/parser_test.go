@@ -0,0 +1,48 @@
+package cge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestContextualKeywords(t *testing.T) {
+	keywords := []string{"import", "true", "false", "union", "const", "extends", "uint", "uint32", "uint64", "bytes", "timestamp", "duration", "uuid"}
+	for _, keyword := range keywords {
+		t.Run(keyword, func(t *testing.T) {
+			source := fmt.Sprintf("name test\nversion 0.4\nevent e {\n  %s: string\n}\nenum x {\n  %s\n}\ntype c {}\nunion u(%s) {\n  c\n}\n", keyword, keyword, keyword)
+			_, objects, errs := Parse(strings.NewReader(source), "dev")
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			for _, object := range objects[:2] {
+				if len(object.Properties) != 1 || object.Properties[0].Name != keyword {
+					t.Errorf("expected '%s' to contain '%s', got %v", object.Name.Lexeme, keyword, object.Properties)
+				}
+			}
+			if discriminator := objects[3].Discriminator; discriminator.Type != IDENTIFIER || discriminator.Lexeme != keyword {
+				t.Errorf("expected the discriminator '%s', got %+v", keyword, discriminator)
+			}
+
+			source = fmt.Sprintf("name test\nversion 0.4\ntype %s {}\n", keyword)
+			if _, _, errs := Parse(strings.NewReader(source), "dev"); len(errs) == 0 {
+				t.Errorf("expected '%s' to be reserved as a declaration name", keyword)
+			}
+		})
+	}
+}
+
+func TestContextualKeywordDefaults(t *testing.T) {
+	source := "name test\nversion 0.4\nconfig {\n  m: mode = import,\n  b: bool = true\n}\nenum mode {\n  import,\n  true\n}\n"
+	_, objects, errs := Parse(strings.NewReader(source), "dev")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	properties := objects[0].Properties
+	if d := properties[0].Default; d == nil || d.Token.Type != IDENTIFIER || d.Value != "import" {
+		t.Errorf("expected the default value of 'm' to be the enum value 'import', got %+v", d)
+	}
+	if d := properties[1].Default; d == nil || d.Token.Type != TRUE {
+		t.Errorf("expected the default value of 'b' to be the boolean 'true', got %+v", d)
+	}
+}
@@ -0,0 +1,149 @@
+package cge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Print writes a syntax tree back out as CGE source in the layout produced by Format.
+// Comments and attributes are kept. Inline types are declared at the top level, imports are not restored
+// and the implicit config object is omitted.
+// Primitive types are written with their canonical names (e.g. 'int32' instead of 'int').
+func Print(metadata Metadata, objects []Object) []byte {
+	var b strings.Builder
+	printComments(&b, "", metadata.Comments)
+	fmt.Fprintf(&b, "name %s\n", metadata.Name)
+	fmt.Fprintf(&b, "version %s\n", metadata.CGEVersion)
+
+	for _, o := range objects {
+		if o.Implicit {
+			continue
+		}
+		b.WriteString("\n")
+		printComments(&b, "", o.Comments)
+		printAttributes(&b, "", o.Attributes, o.Deprecated)
+
+		switch o.Type {
+		case CONST:
+			fmt.Fprintf(&b, "const %s: %s = %s\n", o.Name.Lexeme, typeName(o.ValueType, nil, nil), o.Value)
+			continue
+		case ALIAS:
+			fmt.Fprintf(&b, "type %s = %s\n", o.Name.Lexeme, typeName(o.ValueType, nil, nil))
+			continue
+		}
+
+		b.WriteString(strings.ToLower(string(o.Type)))
+		if o.Scope.Lexeme != "" {
+			fmt.Fprintf(&b, "(%s)", o.Scope.Lexeme)
+		}
+		if o.Type != CONFIG {
+			fmt.Fprintf(&b, " %s", o.Name.Lexeme)
+		}
+		if len(o.TypeParameters) > 0 {
+			names := make([]string, len(o.TypeParameters))
+			for i, t := range o.TypeParameters {
+				names[i] = t.Lexeme
+			}
+			fmt.Fprintf(&b, "<%s>", strings.Join(names, ", "))
+		}
+		if o.Extends != nil {
+			fmt.Fprintf(&b, " extends %s", o.Extends.Token.Lexeme)
+		}
+		if len(o.Responses) > 0 {
+			names := make([]string, len(o.Responses))
+			for i, r := range o.Responses {
+				names[i] = r.Lexeme
+			}
+			fmt.Fprintf(&b, " -> %s", strings.Join(names, ", "))
+		}
+		if o.Type == ENUM && o.ValueType != nil {
+			fmt.Fprintf(&b, ": %s", typeName(o.ValueType, nil, nil))
+		}
+		if o.Type == UNION {
+			fmt.Fprintf(&b, "(%s)", o.Discriminator.Lexeme)
+		}
+
+		if len(o.Properties) == 0 {
+			b.WriteString(" {}\n")
+			continue
+		}
+		b.WriteString(" {\n")
+		for i, p := range o.Properties {
+			if i > 0 {
+				b.WriteString(",\n")
+			}
+			printComments(&b, "\t", p.Comments)
+			printAttributes(&b, "\t", p.Attributes, p.Deprecated)
+			b.WriteString("\t" + p.Name)
+			switch o.Type {
+			case ENUM:
+				if p.Value != nil {
+					fmt.Fprintf(&b, " = %s", p.Value)
+				}
+			case UNION:
+			default:
+				printProperty(&b, p)
+			}
+		}
+		b.WriteString("\n}\n")
+	}
+
+	return []byte(b.String())
+}
+
+// printProperty writes everything after the name of a property.
+func printProperty(b *strings.Builder, p Property) {
+	if p.Optional {
+		b.WriteString("?")
+	}
+	fmt.Fprintf(b, ": %s", typeName(p.Type, nil, nil))
+	if len(p.Constraints) > 0 {
+		constraints := make([]string, len(p.Constraints))
+		for i, c := range p.Constraints {
+			constraints[i] = c.String()
+		}
+		fmt.Fprintf(b, " [%s]", strings.Join(constraints, ", "))
+	}
+	if p.Default != nil {
+		fmt.Fprintf(b, " = %s", p.Default)
+	}
+}
+
+func printComments(b *strings.Builder, indent string, comments []string) {
+	for _, c := range comments {
+		for _, line := range strings.Split(c, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				b.WriteString(indent + "//\n")
+			} else {
+				b.WriteString(indent + "// " + line + "\n")
+			}
+		}
+	}
+}
+
+// printAttributes writes all attributes on their own lines.
+// If attributes doesn't contain a '@deprecated' attribute, it is created from deprecation.
+func printAttributes(b *strings.Builder, indent string, attributes []Attribute, deprecation *Deprecation) {
+	if deprecation != nil && findAttribute(attributes, "deprecated") == nil {
+		attribute := Attribute{
+			Name: Token{Type: IDENTIFIER, Lexeme: "deprecated"},
+		}
+		if deprecation.Reason != "" {
+			attribute.Arguments = []Literal{{Token: Token{Type: STRING_LITERAL}, Value: deprecation.Reason}}
+		}
+		attributes = append(attributes, attribute)
+	}
+
+	for _, a := range attributes {
+		b.WriteString(indent + "@" + a.Name.Lexeme)
+		if len(a.Arguments) > 0 {
+			arguments := make([]string, len(a.Arguments))
+			for i, l := range a.Arguments {
+				arguments[i] = l.String()
+			}
+			fmt.Fprintf(b, "(%s)", strings.Join(arguments, ", "))
+		}
+		b.WriteString("\n")
+	}
+}
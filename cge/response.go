@@ -0,0 +1,36 @@
+package cge
+
+import "fmt"
+
+// responses parses the names of the response events of a command. The '->' must already be consumed.
+func (p *parser) responses() ([]Token, error) {
+	responses := make([]Token, 0)
+	names := make(map[string]struct{})
+	for {
+		if !p.match(IDENTIFIER) {
+			return nil, p.newError(CodeSyntax, "Expect event name after '->'.", false)
+		}
+		response := p.previous()
+		if _, ok := names[response.Lexeme]; ok {
+			return nil, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Duplicate response '%s'.", response.Lexeme), response, false)
+		}
+		names[response.Lexeme] = struct{}{}
+		responses = append(responses, response)
+
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	return responses, nil
+}
+
+// checkResponses makes sure that all responses of commands are events.
+func (p *parser) checkResponses() {
+	for _, o := range p.objects {
+		for _, r := range o.Responses {
+			if _, ok := p.events[r.Lexeme]; !ok {
+				p.errors = append(p.errors, p.newErrorAt(CodeUndefinedEvent, fmt.Sprintf("Undefined event '%s'.", r.Lexeme), r, false))
+			}
+		}
+	}
+}
@@ -9,24 +9,46 @@ import (
 
 type scanner struct {
 	inputScanner     *bufio.Scanner
+	filename         string
 	lines            [][]rune
 	line             int
 	tokenStartColumn int
 	currentColumn    int
 	tokens           []Token
+	errors           []error
+	// lossless makes the scanner emit comments exactly as they appear in the source (including '//', '/*' and '*/').
+	// A block comment results in a single COMMENT token, which can span multiple lines.
+	lossless bool
 }
 
-func scan(source io.Reader) ([]Token, [][]rune, error) {
+// scan returns the tokens and lines of source and all scan errors. Invalid input results in an ERROR token.
+// err is only non-nil if source could not be read.
+func scan(source io.Reader, filename string) (tokens []Token, lines [][]rune, errs []error, err error) {
 	fileScanner := bufio.NewScanner(source)
 
 	srcScanner := &scanner{
 		inputScanner: fileScanner,
+		filename:     filename,
 		line:         -1,
 	}
 
-	err := srcScanner.scan()
+	err = srcScanner.scan()
 
-	return srcScanner.tokens, srcScanner.lines, err
+	return srcScanner.tokens, srcScanner.lines, srcScanner.errors, err
+}
+
+// scanLossless scans source like scan but keeps the original text of all comments.
+func scanLossless(source io.Reader, filename string) (tokens []Token, lines [][]rune, errs []error, err error) {
+	srcScanner := &scanner{
+		inputScanner: bufio.NewScanner(source),
+		filename:     filename,
+		line:         -1,
+		lossless:     true,
+	}
+
+	err = srcScanner.scan()
+
+	return srcScanner.tokens, srcScanner.lines, srcScanner.errors, err
 }
 
 func (s *scanner) scan() error {
@@ -41,6 +63,14 @@ func (s *scanner) scan() error {
 			s.addToken(OPEN_CURLY)
 		case '}':
 			s.addToken(CLOSE_CURLY)
+		case '[':
+			s.addToken(OPEN_SQUARE)
+		case ']':
+			s.addToken(CLOSE_SQUARE)
+		case '(':
+			s.addToken(OPEN_PAREN)
+		case ')':
+			s.addToken(CLOSE_PAREN)
 		case '/':
 			if s.match('/') {
 				s.comment()
@@ -54,26 +84,38 @@ func (s *scanner) scan() error {
 			s.addToken(COLON)
 		case ',':
 			s.addToken(COMMA)
+		case '?':
+			s.addToken(QUESTION)
+		case '=':
+			s.addToken(EQUAL)
 		case '<':
 			s.addToken(LESS)
 		case '>':
 			s.addToken(GREATER)
+		case '@':
+			s.addToken(AT)
+		case '-':
+			if s.match('>') {
+				s.addToken(ARROW)
+			} else if isDigit(s.peek()) {
+				s.number()
+			} else {
+				s.errorToken(CodeInvalidCharacter, fmt.Sprintf("Unexpected character '%c'.", c))
+			}
+		case '"':
+			s.stringLiteral()
 		case ' ', '\t':
 			break
 
 		default:
 			if isLowerAlpha(c) {
-				err := s.identifier()
-				if err != nil {
-					return err
-				}
+				s.identifier()
+			} else if isDigit(c) && s.previousTokenType() == VERSION {
+				s.versionNumber()
 			} else if isDigit(c) {
-				err := s.versionNumber()
-				if err != nil {
-					return err
-				}
+				s.number()
 			} else {
-				return s.newError(fmt.Sprintf("Unexpected character '%c'.", c))
+				s.invalidCharacters()
 			}
 		}
 
@@ -88,6 +130,7 @@ func (s *scanner) scan() error {
 		Line:   s.line,
 		Type:   EOF,
 		Lexeme: "",
+		File:   s.filename,
 	}
 	if s.line >= 0 && s.line < len(s.lines) {
 		eof.Column = len(s.lines[s.line])
@@ -98,7 +141,7 @@ func (s *scanner) scan() error {
 	return nil
 }
 
-func (s *scanner) identifier() error {
+func (s *scanner) identifier() {
 	for isLowerAlphaNum(s.peek()) {
 		s.nextCharacter()
 	}
@@ -110,6 +153,8 @@ func (s *scanner) identifier() error {
 		s.addToken(NAME)
 	case "version":
 		s.addToken(VERSION)
+	case "import":
+		s.addToken(IMPORT)
 	case "config":
 		s.addToken(CONFIG)
 	case "event":
@@ -120,6 +165,12 @@ func (s *scanner) identifier() error {
 		s.addToken(TYPE)
 	case "enum":
 		s.addToken(ENUM)
+	case "union":
+		s.addToken(UNION)
+	case "const":
+		s.addToken(CONST)
+	case "extends":
+		s.addToken(EXTENDS)
 	case "string":
 		s.addToken(STRING)
 	case "bool":
@@ -132,35 +183,85 @@ func (s *scanner) identifier() error {
 		s.addToken(FLOAT32)
 	case "float", "float64":
 		s.addToken(FLOAT64)
+	case "uint", "uint32":
+		s.addToken(UINT32)
+	case "uint64":
+		s.addToken(UINT64)
+	case "bytes":
+		s.addToken(BYTES)
+	case "timestamp":
+		s.addToken(TIMESTAMP)
+	case "duration":
+		s.addToken(DURATION)
+	case "uuid":
+		s.addToken(UUID)
 	case "list":
 		s.addToken(LIST)
 	case "map":
 		s.addToken(MAP)
+	case "true":
+		s.addToken(TRUE)
+	case "false":
+		s.addToken(FALSE)
 	default:
 		s.addToken(IDENTIFIER)
 	}
-
-	return nil
 }
 
-func (s *scanner) versionNumber() error {
+func (s *scanner) versionNumber() {
 	for isDigit(s.peek()) {
 		s.nextCharacter()
 	}
 
 	if !s.match('.') {
-		return s.newError("Expect '.' after major version.")
+		s.errorToken(CodeInvalidVersion, "Expect '.' after major version.")
+		return
 	}
 
 	if !isDigit(s.peek()) {
-		return s.newError("Expect digit after '.'.")
+		s.errorToken(CodeInvalidVersion, "Expect digit after '.'.")
+		return
 	}
 	for isDigit(s.peek()) {
 		s.nextCharacter()
 	}
 
 	s.addToken(VERSION_NUMBER)
-	return nil
+}
+
+func (s *scanner) number() {
+	for isDigit(s.peek()) {
+		s.nextCharacter()
+	}
+
+	if s.peek() == '.' && isDigit(s.peekNext()) {
+		s.nextCharacter()
+		for isDigit(s.peek()) {
+			s.nextCharacter()
+		}
+	}
+
+	s.addToken(NUMBER)
+}
+
+func (s *scanner) stringLiteral() {
+	for s.peek() != '"' {
+		if s.peek() == '\n' {
+			s.errorToken(CodeUnterminatedString, "Unterminated string.")
+			return
+		}
+		if s.peek() == '\\' {
+			s.nextCharacter()
+			if s.peek() == '\n' {
+				s.errorToken(CodeUnterminatedString, "Unterminated string.")
+				return
+			}
+		}
+		s.nextCharacter()
+	}
+	s.nextCharacter()
+
+	s.addToken(STRING_LITERAL)
 }
 
 func (s *scanner) comment() {
@@ -168,17 +269,33 @@ func (s *scanner) comment() {
 	for s.peek() != '\n' {
 		s.nextCharacter()
 	}
+	text := s.lines[s.line][startColumn : s.currentColumn+1]
+	if s.lossless {
+		s.tokens = append(s.tokens, Token{
+			Line:   s.line,
+			Column: startColumn - 2,
+			Type:   COMMENT,
+			Lexeme: strings.TrimRight(string(s.lines[s.line][startColumn-2:s.currentColumn+1]), " \t"),
+			File:   s.filename,
+		})
+		return
+	}
 	s.tokens = append(s.tokens, Token{
 		Line:   s.line,
-		Column: startColumn,
+		Column: startColumn + textOffset(text),
 		Type:   COMMENT,
-		Lexeme: strings.TrimSpace(string(s.lines[s.line][startColumn : s.currentColumn+1])),
+		Lexeme: strings.TrimSpace(string(text)),
+		File:   s.filename,
 	})
 }
 
 func (s *scanner) blockComment() error {
-	startColumn := s.currentColumn + 1
+	startLine := s.line
+	startColumn := s.tokenStartColumn
 	lines := make([][]rune, 0)
+	// columns contains the column of the first character of each line in lines.
+	columns := make([]int, 0)
+	column := s.currentColumn + 1
 
 	nestingLevel := 1
 	prevLine := s.line
@@ -186,9 +303,20 @@ func (s *scanner) blockComment() error {
 	for nestingLevel > 0 {
 		c, err := s.nextCharacter()
 
-		if c == '\000' || err != nil {
+		if err != nil {
 			return err
 		}
+		if c == '\000' {
+			s.errors = append(s.errors, s.newError(CodeUnterminatedComment, "Unterminated block comment."))
+			return nil
+		}
+		if prevLine != s.line {
+			prevLine = s.line
+			lines = append(lines, line)
+			columns = append(columns, column)
+			line = make([]rune, 0)
+		}
+
 		if c == '/' && s.match('*') {
 			nestingLevel++
 			continue
@@ -198,22 +326,49 @@ func (s *scanner) blockComment() error {
 			continue
 		}
 
-		if prevLine != s.line {
-			prevLine = s.line
-			lines = append(lines, line)
-			line = make([]rune, 0)
+		if len(line) == 0 {
+			column = s.currentColumn
 		}
 		line = append(line, c)
 	}
+	lines = append(lines, line)
+	columns = append(columns, column)
+
+	if s.lossless {
+		raw := make([]string, 0, s.line-startLine+1)
+		for l := startLine; l <= s.line; l++ {
+			text := s.lines[l]
+			if l == s.line {
+				text = text[:s.currentColumn+1]
+			}
+			if l == startLine {
+				text = text[startColumn:]
+			}
+			raw = append(raw, strings.TrimRight(string(text), " \t"))
+		}
+		s.tokens = append(s.tokens, Token{
+			Line:   startLine,
+			Column: startColumn,
+			Type:   COMMENT,
+			Lexeme: strings.Join(raw, "\n"),
+			File:   s.filename,
+		})
+		return nil
+	}
 
 	for i, l := range lines {
 		text := strings.TrimSpace(strings.Replace(string(l), "*", "", 1))
 		if text != "" {
+			offset := 0
+			if index := strings.Index(string(l), text); index >= 0 {
+				offset = len([]rune(string(l)[:index]))
+			}
 			s.tokens = append(s.tokens, Token{
 				Line:   (prevLine - len(lines)) + i + 1,
-				Column: startColumn,
+				Column: columns[i] + offset,
 				Type:   COMMENT,
 				Lexeme: text,
+				File:   s.filename,
 			})
 		}
 	}
@@ -275,7 +430,55 @@ func (s *scanner) addToken(tokenType TokenType) {
 		Column: s.tokenStartColumn,
 		Type:   tokenType,
 		Lexeme: string(s.lines[s.line][s.tokenStartColumn : s.currentColumn+1]),
+		File:   s.filename,
+	})
+}
+
+// invalidCharacters adds a single ERROR token and scan error for the run of invalid characters starting at the current character.
+func (s *scanner) invalidCharacters() {
+	for isInvalidCharacter(s.peek()) {
+		s.nextCharacter()
+	}
+	text := s.lines[s.line][s.tokenStartColumn : s.currentColumn+1]
+	msg := fmt.Sprintf("Unexpected character '%s'.", string(text))
+	if len(text) > 1 {
+		msg = fmt.Sprintf("Unexpected characters '%s'.", string(text))
+	}
+	s.errors = append(s.errors, ScanError{
+		File:     s.filename,
+		Line:     s.line,
+		LineText: s.lines[s.line],
+		Column:   s.tokenStartColumn,
+		Length:   len(text),
+		Code:     CodeInvalidCharacter,
+		Message:  msg,
 	})
+	s.addToken(ERROR)
+}
+
+// errorToken records a scan error and adds an ERROR token containing the invalid input, so that scanning can continue.
+func (s *scanner) errorToken(code Code, msg string) {
+	s.errors = append(s.errors, s.newError(code, msg))
+	s.addToken(ERROR)
+}
+
+func (s *scanner) previousTokenType() TokenType {
+	for i := len(s.tokens) - 1; i >= 0; i-- {
+		if s.tokens[i].Type != COMMENT {
+			return s.tokens[i].Type
+		}
+	}
+	return EOF
+}
+
+// textOffset returns the number of leading white space characters in text.
+func textOffset(text []rune) int {
+	for i, c := range text {
+		if c != ' ' && c != '\t' {
+			return i
+		}
+	}
+	return len(text)
 }
 
 func isDigit(char rune) bool {
@@ -290,22 +493,51 @@ func isLowerAlphaNum(char rune) bool {
 	return isDigit(char) || isLowerAlpha(char)
 }
 
+// isInvalidCharacter returns true if char cannot start any token or be skipped as whitespace.
+func isInvalidCharacter(char rune) bool {
+	return !isLowerAlphaNum(char) && !strings.ContainsRune("{}[]():,?=<>@\"/- \t\n", char)
+}
+
 type ScanError struct {
+	File     string
 	Line     int
 	LineText []rune
 	Column   int
-	Message  string
+	// Length is the number of characters covered by the error. Zero is treated as one.
+	Length  int
+	Code    Code
+	Message string
 }
 
 func (s ScanError) Error() string {
-	return generateErrorText(s.Message, s.LineText, s.Line, s.Column, s.Column+1)
+	return s.Diagnostic().Error()
+}
+
+// Diagnostic converts the error into a diagnostic which covers Length characters starting at Column.
+func (s ScanError) Diagnostic() Diagnostic {
+	length := s.Length
+	if length < 1 {
+		length = 1
+	}
+	return Diagnostic{
+		Severity: SeverityError,
+		Code:     s.Code,
+		Message:  s.Message,
+		Range: Range{
+			Start: Position{File: s.File, Line: s.Line, Column: s.Column},
+			End:   Position{File: s.File, Line: s.Line, Column: s.Column + length},
+		},
+		Source: string(s.LineText),
+	}
 }
 
-func (s *scanner) newError(msg string) error {
+func (s *scanner) newError(code Code, msg string) error {
 	return ScanError{
+		File:     s.filename,
 		Line:     s.line,
 		LineText: s.lines[s.line],
 		Column:   s.currentColumn,
+		Code:     code,
 		Message:  msg,
 	}
 }
@@ -0,0 +1,185 @@
+package cge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanReportsMultipleErrors(t *testing.T) {
+	source := `name test
+version 0.9
+event $hello {
+  a: strin#g,
+  b: string = "abc
+}
+type x { y: int ~ }
+`
+	tokens, _, errs, err := scan(strings.NewReader(source), "test.cge")
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+
+	expected := []struct {
+		code   Code
+		line   int
+		column int
+	}{
+		{CodeInvalidCharacter, 2, 6},
+		{CodeInvalidCharacter, 3, 10},
+		{CodeUnterminatedString, 4, 17},
+		{CodeInvalidCharacter, 6, 16},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i, e := range expected {
+		scanErr, ok := errs[i].(ScanError)
+		if !ok {
+			t.Fatalf("error %d: expected ScanError, got %T", i, errs[i])
+		}
+		if scanErr.Code != e.code || scanErr.Line != e.line || scanErr.Column != e.column {
+			t.Errorf("error %d: expected %s at %d:%d, got %s at %d:%d", i, e.code, e.line, e.column, scanErr.Code, scanErr.Line, scanErr.Column)
+		}
+	}
+
+	errorTokens := 0
+	for _, token := range tokens {
+		if token.Type == ERROR {
+			errorTokens++
+		}
+	}
+	if errorTokens != len(expected) {
+		t.Errorf("expected %d ERROR tokens, got %d", len(expected), errorTokens)
+	}
+
+	last := tokens[len(tokens)-2]
+	if last.Type != CLOSE_CURLY || last.Line != 6 {
+		t.Errorf("expected scanning to continue until the last line, last token: %+v", last)
+	}
+}
+
+func TestScanErrorTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		code   Code
+		lexeme string
+	}{
+		{"unexpected character", "a $ b", CodeInvalidCharacter, "$"},
+		{"run of unexpected characters", "a GREETING b", CodeInvalidCharacter, "GREETING"},
+		{"run of unexpected characters before a token", "a $$:b", CodeInvalidCharacter, "$$"},
+		{"single minus", "a - b", CodeInvalidCharacter, "-"},
+		{"unterminated string", `a "bc`, CodeUnterminatedString, `"bc`},
+		{"missing minor version", "version 1 a", CodeInvalidVersion, "1"},
+		{"missing minor version digit", "version 1. a", CodeInvalidVersion, "1."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens, _, errs, err := scan(strings.NewReader(test.source), "")
+			if err != nil {
+				t.Fatalf("unexpected read error: %s", err)
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+			}
+			if code := errs[0].(ScanError).Code; code != test.code {
+				t.Errorf("expected code %s, got %s", test.code, code)
+			}
+
+			var errorToken *Token
+			for i := range tokens {
+				if tokens[i].Type == ERROR {
+					errorToken = &tokens[i]
+				}
+			}
+			if errorToken == nil {
+				t.Fatalf("expected an ERROR token")
+			}
+			if errorToken.Lexeme != test.lexeme {
+				t.Errorf("expected ERROR token %q, got %q", test.lexeme, errorToken.Lexeme)
+			}
+			if tokens[len(tokens)-2].Type != IDENTIFIER && test.code != CodeUnterminatedString {
+				t.Errorf("expected scanning to continue after the error, tokens: %v", tokens)
+			}
+		})
+	}
+}
+
+func TestParseReportsScanAndSyntaxErrors(t *testing.T) {
+	source := `name test
+version 0.9
+event $hello {}
+type x { y: int ~ }
+command c { z bool }
+type ok { v: string }
+`
+	file, errs := ParseAST(strings.NewReader(source), "test.cge", "dev")
+	if file == nil {
+		t.Fatalf("expected a file, got errors: %v", errs)
+	}
+
+	codes := make([]Code, len(errs))
+	for i, err := range errs {
+		codes[i] = AsDiagnostic(err).Code
+	}
+	expected := []Code{CodeInvalidCharacter, CodeInvalidCharacter, CodeSyntax}
+	if len(codes) != len(expected) {
+		t.Fatalf("expected errors %v, got %v: %v", expected, codes, errs)
+	}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Errorf("error %d: expected %s, got %s", i, expected[i], codes[i])
+		}
+	}
+
+	for _, err := range errs {
+		if e, ok := err.(ParseError); ok && e.Token.Type == ERROR {
+			t.Errorf("unexpected parse error at ERROR token: %s", e.Message)
+		}
+	}
+
+	found := false
+	for _, o := range file.Objects {
+		if o.Name.Lexeme == "ok" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected declarations after the errors to be parsed")
+	}
+}
+
+func TestScanUnterminatedBlockComment(t *testing.T) {
+	source := `name test
+/* a comment
+   without an end
+type x {}
+`
+	tokens, _, errs, err := scan(strings.NewReader(source), "test.cge")
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if code := errs[0].(ScanError).Code; code != CodeUnterminatedComment {
+		t.Errorf("expected code %s, got %s", CodeUnterminatedComment, code)
+	}
+	if last := tokens[len(tokens)-1]; last.Type != EOF {
+		t.Errorf("expected the last token to be EOF, got %+v", last)
+	}
+}
+
+func TestScanErrorCoversRunOfInvalidCharacters(t *testing.T) {
+	_, _, errs, err := scan(strings.NewReader("name GREETING"), "test.cge")
+	if err != nil {
+		t.Fatalf("unexpected read error: %s", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	r := errs[0].(ScanError).Diagnostic().Range
+	if r.Start.Column != 5 || r.End.Column != 13 {
+		t.Errorf("expected the error to cover columns 5-13, got %d-%d", r.Start.Column, r.End.Column)
+	}
+}
@@ -0,0 +1,37 @@
+package cge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventScopes are all valid scopes of events:
+//   - private events are only sent to the affected player
+//   - spectator events are only sent to spectators
+//   - broadcast events are sent to all players
+var EventScopes = []string{"private", "spectator", "broadcast"}
+
+// eventScope parses the scope of an event. The '(' must already be consumed.
+func (p *parser) eventScope() (Token, error) {
+	if !p.match(IDENTIFIER) {
+		return Token{}, p.newError(CodeSyntax, "Expect event scope after '('.", false)
+	}
+	scope := p.previous()
+
+	valid := false
+	for _, s := range EventScopes {
+		if scope.Lexeme == s {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return Token{}, p.newErrorAt(CodeInvalidScope, fmt.Sprintf("Unknown event scope '%s'. Expect '%s'.", scope.Lexeme, strings.Join(EventScopes, "', '")), scope, false)
+	}
+
+	if !p.match(CLOSE_PAREN) {
+		return Token{}, p.newError(CodeSyntax, "Expect ')' after event scope.", false)
+	}
+
+	return scope, nil
+}
@@ -5,12 +5,20 @@ type TokenType string
 const (
 	NAME    TokenType = "NAME"
 	VERSION TokenType = "VERSION"
+	IMPORT  TokenType = "IMPORT"
 
 	CONFIG  TokenType = "CONFIG"
 	COMMAND TokenType = "COMMAND"
 	EVENT   TokenType = "EVENT"
 	TYPE    TokenType = "TYPE"
 	ENUM    TokenType = "ENUM"
+	UNION   TokenType = "UNION"
+	CONST   TokenType = "CONST"
+	EXTENDS TokenType = "EXTENDS"
+	// ALIAS is not produced by the scanner. It is the type of objects declared with 'type NAME = TYPE'.
+	ALIAS TokenType = "ALIAS"
+	// TYPE_PARAMETER is not produced by the scanner. It is the type of property types which refer to a type parameter of a generic type.
+	TYPE_PARAMETER TokenType = "TYPE_PARAMETER"
 
 	STRING  TokenType = "STRING"
 	BOOL    TokenType = "BOOL"
@@ -18,28 +26,73 @@ const (
 	INT64   TokenType = "INT64"
 	FLOAT32 TokenType = "FLOAT32"
 	FLOAT64 TokenType = "FLOAT64"
+	// UINT32 and UINT64 are encoded as JSON numbers.
+	UINT32 TokenType = "UINT32"
+	UINT64 TokenType = "UINT64"
+	// BYTES is encoded as a standard base64 string with padding.
+	BYTES TokenType = "BYTES"
+	// TIMESTAMP is encoded as an RFC 3339 string.
+	TIMESTAMP TokenType = "TIMESTAMP"
+	// DURATION is encoded as a JSON number containing the duration in nanoseconds.
+	DURATION TokenType = "DURATION"
+	// UUID is encoded as a string in the canonical 8-4-4-4-12 format.
+	UUID TokenType = "UUID"
 
 	MAP  TokenType = "MAP"
 	LIST TokenType = "LIST"
 
+	TRUE  TokenType = "TRUE"
+	FALSE TokenType = "FALSE"
+
 	IDENTIFIER     TokenType = "IDENTIFIER"
 	VERSION_NUMBER TokenType = "VERSION_NUMBER"
+	STRING_LITERAL TokenType = "STRING_LITERAL"
+	NUMBER         TokenType = "NUMBER"
 
-	OPEN_CURLY  TokenType = "OPEN_CURLY"
-	CLOSE_CURLY TokenType = "CLOSE_CURLY"
-	COLON       TokenType = "COLON"
-	COMMA       TokenType = "COMMA"
-	GREATER     TokenType = "GREATER"
-	LESS        TokenType = "LESS"
+	OPEN_CURLY   TokenType = "OPEN_CURLY"
+	CLOSE_CURLY  TokenType = "CLOSE_CURLY"
+	OPEN_SQUARE  TokenType = "OPEN_SQUARE"
+	CLOSE_SQUARE TokenType = "CLOSE_SQUARE"
+	OPEN_PAREN   TokenType = "OPEN_PAREN"
+	CLOSE_PAREN  TokenType = "CLOSE_PAREN"
+	COLON        TokenType = "COLON"
+	COMMA        TokenType = "COMMA"
+	QUESTION     TokenType = "QUESTION"
+	EQUAL        TokenType = "EQUAL"
+	GREATER      TokenType = "GREATER"
+	LESS         TokenType = "LESS"
+	AT           TokenType = "AT"
+	ARROW        TokenType = "ARROW"
 
 	COMMENT TokenType = "COMMENT"
+	// ERROR contains invalid input, e.g. a whole run of invalid characters. The scanner reports one error for every ERROR token and continues after it.
+	ERROR TokenType = "ERROR"
 
 	EOF TokenType = "EOF"
 )
 
+// contextualKeywords are keywords which were added after CGE v0.4.
+// They are reserved as declaration names but can still be used as property names, enum value names and union discriminators to keep existing CGE files valid.
+var contextualKeywords = map[TokenType]struct{}{
+	IMPORT:  {},
+	TRUE:    {},
+	FALSE:   {},
+	UNION:   {},
+	CONST:   {},
+	EXTENDS: {},
+
+	UINT32:    {},
+	UINT64:    {},
+	BYTES:     {},
+	TIMESTAMP: {},
+	DURATION:  {},
+	UUID:      {},
+}
+
 type Token struct {
 	Type   TokenType
 	Lexeme string
 	Line   int
 	Column int
+	File   string
 }
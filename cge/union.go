@@ -0,0 +1,93 @@
+package cge
+
+import "fmt"
+
+func (p *parser) unionBlock() ([]Property, error) {
+	members := make([]Property, 0)
+
+	for p.peek().Type != EOF && p.peek().Type != CLOSE_CURLY {
+		member, err := p.unionMember()
+		if err != nil {
+			p.errors = append(p.errors, err)
+			p.skipProperty()
+			continue
+		}
+		members = append(members, member)
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	if !p.match(CLOSE_CURLY) {
+		return nil, p.newError(CodeSyntax, "Expect '}' after block.", true)
+	}
+
+	return members, nil
+}
+
+func (p *parser) unionMember() (Property, error) {
+	var comments []Token
+	for p.match(COMMENT) {
+		comments = append(comments, p.previous())
+	}
+
+	if !p.match(IDENTIFIER) {
+		return Property{}, p.newError(CodeSyntax, "Expect type name.", true)
+	}
+	name := p.previous()
+	p.accessedTypeIdentifiers = append(p.accessedTypeIdentifiers, name)
+
+	return Property{
+		Comments:      commentLexemes(comments),
+		CommentTokens: comments,
+		Name:          name.Lexeme,
+		NameToken:     name,
+		Type: &PropertyType{
+			Token: name,
+			Range: name.Range(),
+		},
+		Range: name.Range(),
+	}, nil
+}
+
+// checkUnions makes sure that all union members are types which don't already contain the discriminator property.
+func (p *parser) checkUnions() {
+	objects := make(map[string]Object, len(p.objects))
+	for _, o := range p.objects {
+		objects[o.Name.Lexeme] = o
+	}
+
+	for _, o := range p.objects {
+		if o.Type != UNION {
+			continue
+		}
+
+		members := make(map[string]struct{}, len(o.Properties))
+		for _, m := range o.Properties {
+			if _, ok := members[m.Name]; ok {
+				p.errors = append(p.errors, p.newErrorAt(CodeDuplicate, fmt.Sprintf("Type '%s' is already a member of union '%s'.", m.Name, o.Name.Lexeme), m.Type.Token, true))
+				continue
+			}
+			members[m.Name] = struct{}{}
+
+			member, ok := objects[m.Name]
+			if !ok {
+				continue
+			}
+			if member.Type != TYPE {
+				p.errors = append(p.errors, p.newErrorAt(CodeInvalidUnion, fmt.Sprintf("Union member '%s' is not a type.", m.Name), m.Type.Token, true))
+				continue
+			}
+			if len(member.TypeParameters) > 0 {
+				p.errors = append(p.errors, p.newErrorAt(CodeInvalidUnion, fmt.Sprintf("Generic type '%s' cannot be a member of a union.", m.Name), m.Type.Token, true))
+				continue
+			}
+			for _, property := range append(InheritedProperties(p.objects, member), member.Properties...) {
+				if property.Name == o.Discriminator.Lexeme {
+					p.errors = append(p.errors, p.newErrorAt(CodeInvalidUnion, fmt.Sprintf("Type '%s' cannot be a member of union '%s' because it already has a property named '%s'.", m.Name, o.Name.Lexeme, property.Name), m.Type.Token, true))
+					break
+				}
+			}
+		}
+	}
+}
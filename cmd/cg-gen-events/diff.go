@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/pflag"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+// Exit codes of the diff command, so that CI pipelines can distinguish breaking changes from invalid input.
+const (
+	exitBreaking  = 1
+	exitDiffError = 2
+)
+
+func runDiff(args []string) {
+	flags := pflag.NewFlagSet("diff", pflag.ExitOnError)
+
+	var format string
+	flags.StringVarP(&format, "format", "f", "text", "The output format: text or json.")
+
+	var diagnosticsFormat string
+	flags.StringVar(&diagnosticsFormat, "diagnostics-format", "color", "The format of errors in the input files: color, plain or json.")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s diff [options] <old-cge-file> <new-cge-file>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nCompares two CGE files and exits with status 1 if there are breaking changes\nor with status 2 if a file can't be read or contains errors.\n")
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(exitDiffError)
+	}
+
+	if format != "text" && format != "json" {
+		cli.Error("Unknown output format: %s", format)
+		os.Exit(exitDiffError)
+	}
+	if diagnosticsFormat != "color" && diagnosticsFormat != "plain" && diagnosticsFormat != "json" {
+		cli.Error("Unknown diagnostics format: %s", diagnosticsFormat)
+		os.Exit(exitDiffError)
+	}
+
+	old := parseInput(flags.Arg(0), diagnosticsFormat, exitDiffError)
+	new := parseInput(flags.Arg(1), diagnosticsFormat, exitDiffError)
+
+	changes := cge.Diff(old, new)
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+
+	if format == "json" {
+		data, err := cge.RenderChangesJSON(changes)
+		if err != nil {
+			cli.Error("Failed to encode changes: %s", err)
+			os.Exit(exitDiffError)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, c := range changes {
+			position := c.New.Start
+			if c.New == (cge.Range{}) {
+				position = c.Old.Start
+			}
+			severity := "compatible"
+			if c.Breaking {
+				severity = "BREAKING"
+			}
+			if position == (cge.Position{}) {
+				fmt.Printf("%s: %s\n", severity, c.Message)
+			} else {
+				fmt.Printf("%s: %s %s\n", severity, position, c.Message)
+			}
+		}
+		if len(changes) == 0 {
+			fmt.Println("No changes.")
+		} else {
+			fmt.Printf("\n%d breaking, %d compatible changes.\n", breaking, len(changes)-breaking)
+		}
+	}
+
+	if breaking > 0 {
+		os.Exit(exitBreaking)
+	}
+}
+
+// parseInput parses a local or remote CGE file and exits with exitCode if the file can't be read or contains errors.
+func parseInput(filename, diagnosticsFormat string, exitCode int) *cge.File {
+	input, filename, err := openInputFile(filename)
+	if err != nil {
+		cli.Error(err.Error())
+		os.Exit(exitCode)
+	}
+	defer input.Close()
+
+	file, errs := cge.ParseAST(input, filename, version)
+	if len(errs) > 0 {
+		diagnostics := make([]cge.Diagnostic, len(errs))
+		for i, e := range errs {
+			diagnostics[i] = cge.AsDiagnostic(e)
+		}
+		printDiagnostics(diagnostics, diagnosticsFormat)
+		os.Exit(exitCode)
+	}
+	return file
+}
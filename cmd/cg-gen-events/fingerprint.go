@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/pflag"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+func runFingerprint(args []string) {
+	flags := pflag.NewFlagSet("fingerprint", pflag.ExitOnError)
+
+	var diagnosticsFormat string
+	flags.StringVar(&diagnosticsFormat, "diagnostics-format", "color", "The format of errors in the input file: color, plain or json.")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s fingerprint [options] <cge-file>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nPrints the schema fingerprint which is included in the generated event definitions.\n")
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	if diagnosticsFormat != "color" && diagnosticsFormat != "plain" && diagnosticsFormat != "json" {
+		cli.Error("Unknown diagnostics format: %s", diagnosticsFormat)
+		os.Exit(1)
+	}
+
+	file := parseInput(flags.Arg(0), diagnosticsFormat, 1)
+	fmt.Println(cge.Fingerprint(file.Metadata, file.Objects))
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/pflag"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+func runFmt(args []string) {
+	flags := pflag.NewFlagSet("fmt", pflag.ExitOnError)
+
+	var write bool
+	flags.BoolVarP(&write, "write", "w", false, "Write the result to the input files instead of stdout.")
+
+	var check bool
+	flags.BoolVar(&check, "check", false, "List the files which are not formatted and exit with status 1 if there are any.")
+
+	var diff bool
+	flags.BoolVarP(&diff, "diff", "d", false, "Print a diff of the changes instead of the formatted files.")
+
+	var diagnosticsFormat string
+	flags.StringVar(&diagnosticsFormat, "diagnostics-format", "color", "The format of syntax errors in the input files: color, plain or json.")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s fmt [options] <cge-file>...\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	if diagnosticsFormat != "color" && diagnosticsFormat != "plain" && diagnosticsFormat != "json" {
+		cli.Error("Unknown diagnostics format: %s", diagnosticsFormat)
+		os.Exit(1)
+	}
+
+	failed := false
+	unformatted := false
+	for _, filename := range flags.Args() {
+		source, err := os.ReadFile(filename)
+		if err != nil {
+			cli.Error("Failed to open input file: %s", err)
+			failed = true
+			continue
+		}
+
+		formatted, errs := cge.Format(bytes.NewReader(source), filename)
+		if len(errs) > 0 {
+			diagnostics := make([]cge.Diagnostic, len(errs))
+			for i, e := range errs {
+				diagnostics[i] = cge.AsDiagnostic(e)
+			}
+			printDiagnostics(diagnostics, diagnosticsFormat)
+			failed = true
+			continue
+		}
+
+		changed := !bytes.Equal(source, formatted)
+		if changed {
+			unformatted = true
+		}
+
+		switch {
+		case check:
+			if changed {
+				fmt.Println(filename)
+			}
+		case diff:
+			if changed {
+				fmt.Print(unifiedDiff(filename, string(source), string(formatted)))
+			}
+		case write:
+			if changed {
+				err = os.WriteFile(filename, formatted, 0o644)
+				if err != nil {
+					cli.Error("Failed to write '%s': %s", filename, err)
+					failed = true
+				}
+			}
+		default:
+			os.Stdout.Write(formatted)
+		}
+	}
+
+	if failed || (check && unformatted) {
+		os.Exit(1)
+	}
+}
+
+// unifiedDiff returns a diff between old and new in the unified format with 3 lines of context.
+func unifiedDiff(filename, old, new string) string {
+	a := strings.SplitAfter(old, "\n")
+	if a[len(a)-1] == "" {
+		a = a[:len(a)-1]
+	}
+	b := strings.SplitAfter(new, "\n")
+	if b[len(b)-1] == "" {
+		b = b[:len(b)-1]
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		kind byte
+		text string
+		// oldLine and newLine are the zero-based line numbers in old and new in front of which the line is located.
+		oldLine, newLine int
+	}
+	lines := make([]line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	const context = 3
+	var text strings.Builder
+	fmt.Fprintf(&text, "--- %s\n+++ %s\n", filename, filename)
+	for start := 0; start < len(lines); {
+		if lines[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// extend the hunk until there are more than 2*context unchanged lines
+		end := start
+		for k := start; k < len(lines) && k-end <= 2*context; k++ {
+			if lines[k].kind != ' ' {
+				end = k + 1
+			}
+		}
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+		last := end + context
+		if last > len(lines) {
+			last = len(lines)
+		}
+
+		oldCount, newCount := 0, 0
+		for _, l := range lines[first:last] {
+			if l.kind != '+' {
+				oldCount++
+			}
+			if l.kind != '-' {
+				newCount++
+			}
+		}
+		oldStart, newStart := lines[first].oldLine+1, lines[first].newLine+1
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&text, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, l := range lines[first:last] {
+			text.WriteByte(l.kind)
+			text.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				text.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = last
+	}
+	return text.String()
+}
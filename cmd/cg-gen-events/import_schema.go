@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/pflag"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+func runImportSchema(args []string) {
+	flags := pflag.NewFlagSet("import-schema", pflag.ExitOnError)
+
+	var output string
+	flags.StringVarP(&output, "output", "o", "", "The CGE file to write the declarations to. They are printed to stdout if empty.")
+
+	var name string
+	flags.StringVar(&name, "name", "", "The name of the game. Defaults to the title of the schema or the name of the input file.")
+
+	var diagnosticsFormat string
+	flags.StringVar(&diagnosticsFormat, "diagnostics-format", "color", "The format of warnings about lossy conversions: color, plain or json.")
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s import-schema [options] <json-schema-file>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nConverts a JSON Schema file into CGE declarations.\n")
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	// Messages must not end up in the output if the declarations are printed to stdout.
+	printError, report := cli.Error, printDiagnostics
+	if output == "" {
+		printError = func(format string, a ...any) {
+			writeError(os.Stderr, format, a...)
+		}
+		report = func(diagnostics []cge.Diagnostic, format string) {
+			writeDiagnostics(os.Stderr, diagnostics, format)
+		}
+	}
+
+	if diagnosticsFormat != "color" && diagnosticsFormat != "plain" && diagnosticsFormat != "json" {
+		printError("Unknown diagnostics format: %s", diagnosticsFormat)
+		os.Exit(1)
+	}
+
+	input, err := os.Open(flags.Arg(0))
+	if err != nil {
+		printError("Failed to open input file: %s", err)
+		os.Exit(1)
+	}
+	defer input.Close()
+
+	file, err := cge.ImportJSONSchema(input, flags.Arg(0), name, cge.LatestVersion)
+	if err != nil {
+		printError("Failed to import '%s': %s", flags.Arg(0), err)
+		os.Exit(1)
+	}
+	report(file.Warnings, diagnosticsFormat)
+
+	source := cge.Print(file.Metadata, file.Objects)
+	filename := output
+	if output == "" {
+		filename = "stdout"
+		os.Stdout.Write(source)
+	} else {
+		err = os.WriteFile(output, source, 0o644)
+		if err != nil {
+			cli.Error("Failed to write '%s': %s", output, err)
+			os.Exit(1)
+		}
+	}
+
+	// Some schemas result in declarations which are invalid in CGE, e.g. recursive types.
+	_, errs := cge.ParseAST(bytes.NewReader(source), filename, version)
+	if len(errs) > 0 {
+		diagnostics := make([]cge.Diagnostic, len(errs))
+		for i, e := range errs {
+			diagnostics[i] = cge.AsDiagnostic(e)
+		}
+		report(diagnostics, diagnosticsFormat)
+		printError("The imported declarations contain errors which have to be fixed manually.")
+		os.Exit(1)
+	}
+}
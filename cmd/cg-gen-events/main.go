@@ -59,8 +59,8 @@ var availableGenerators = []generator{
 	},
 }
 
-func openInputFile(filename string) (io.ReadCloser, error) {
-	if strings.HasPrefix(pflag.Arg(0), "http://") || strings.HasPrefix(pflag.Arg(0), "https://") {
+func openInputFile(filename string) (io.ReadCloser, string, error) {
+	if strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://") {
 		if !strings.HasSuffix(filename, "/api/events") && !strings.HasSuffix(filename, ".cge") {
 			if strings.HasSuffix(filename, "/api") {
 				filename += "/events"
@@ -72,32 +72,98 @@ func openInputFile(filename string) (io.ReadCloser, error) {
 		}
 		resp, err := http.Get(filename)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to reach url '%s': %s", filename, err)
+			return nil, "", fmt.Errorf("Failed to reach url '%s': %s", filename, err)
 		}
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("Failed to download CGE file from url '%s'", filename)
+			return nil, "", fmt.Errorf("Failed to download CGE file from url '%s'", filename)
 		}
 		if !strings.Contains(resp.Header.Get("Content-Type"), "text/plain") {
-			return nil, fmt.Errorf("Unsupported content type at '%s': expected %s, got %s\n", filename, "text/plain", resp.Header.Get("Content-Type"))
+			return nil, "", fmt.Errorf("Unsupported content type at '%s': expected %s, got %s\n", filename, "text/plain", resp.Header.Get("Content-Type"))
 		}
-		return resp.Body, err
+		return resp.Body, filename, err
 	}
 
-	input, err := os.Open(pflag.Arg(0))
+	input, err := os.Open(filename)
 	if err != nil {
-		cli.Error("Failed to open input file: %s", err)
-		os.Exit(1)
+		return nil, "", fmt.Errorf("Failed to open input file: %s", err)
+	}
+	return input, filename, nil
+}
+
+func printDiagnostics(diagnostics []cge.Diagnostic, format string) {
+	if format != "json" && format != "plain" {
+		for _, d := range diagnostics {
+			if d.Severity == cge.SeverityWarning {
+				cli.Warn("%s", cge.RenderColored(d))
+			} else {
+				cli.Error("%s", cge.RenderColored(d))
+			}
+		}
+		return
 	}
-	return input, err
+	writeDiagnostics(os.Stdout, diagnostics, format)
+}
+
+// writeDiagnostics writes diagnostics to w, e.g. to stderr if stdout contains the output of a command.
+func writeDiagnostics(w io.Writer, diagnostics []cge.Diagnostic, format string) {
+	switch format {
+	case "json":
+		if len(diagnostics) == 0 {
+			return
+		}
+		data, err := cge.RenderJSON(diagnostics)
+		if err != nil {
+			writeError(w, "Failed to encode diagnostics: %s", err)
+			return
+		}
+		fmt.Fprintln(w, string(data))
+	case "plain":
+		for _, d := range diagnostics {
+			fmt.Fprintf(w, "%s: %s\n", strings.ToUpper(string(d.Severity)), cge.RenderPlain(d))
+		}
+	default:
+		for _, d := range diagnostics {
+			if d.Severity == cge.SeverityWarning {
+				fmt.Fprintf(w, "%sWARNING: %s%s\n", cli.Yellow, cli.Reset, cge.RenderColored(d))
+			} else {
+				writeError(w, "%s", cge.RenderColored(d))
+			}
+		}
+	}
+}
+
+// writeError writes an error message to w like cli.Error writes it to stdout.
+func writeError(w io.Writer, format string, a ...any) {
+	fmt.Fprintf(w, "%sERROR: %s%s\n", cli.RedBold, cli.Reset, fmt.Sprintf(format, a...))
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fmt":
+			runFmt(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "fingerprint":
+			runFingerprint(os.Args[2:])
+			return
+		case "import-schema":
+			runImportSchema(os.Args[2:])
+			return
+		}
+	}
+
 	var languages string
 	pflag.StringVarP(&languages, "languages", "l", "", "A comma separated list of target languages (e.g. \"go,typescript\" or \"all\" for all supported languages).")
 
 	var output string
 	pflag.StringVarP(&output, "output", "o", ".", "The directory where every generated file will be put into. (Will be created if it does not exist.)")
 
+	var diagnosticsFormat string
+	pflag.StringVar(&diagnosticsFormat, "diagnostics-format", "color", "The format of errors and warnings in the input file: color, plain or json.")
+
 	pflag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options] <cge-file>\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
@@ -111,7 +177,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	input, err := openInputFile(pflag.Arg(0))
+	if diagnosticsFormat != "color" && diagnosticsFormat != "plain" && diagnosticsFormat != "json" {
+		cli.Error("Unknown diagnostics format: %s", diagnosticsFormat)
+		os.Exit(1)
+	}
+
+	input, filename, err := openInputFile(pflag.Arg(0))
 	if err != nil {
 		cli.Error(err.Error())
 		os.Exit(1)
@@ -123,13 +194,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	metadata, objects, errs := cge.Parse(input, version)
+	file, errs := cge.ParseAST(input, filename, version)
+	var diagnostics []cge.Diagnostic
+	if file != nil {
+		diagnostics = append(diagnostics, file.Warnings...)
+	}
+	for _, e := range errs {
+		diagnostics = append(diagnostics, cge.AsDiagnostic(e))
+	}
+	printDiagnostics(diagnostics, diagnosticsFormat)
 	if len(errs) > 0 {
-		for _, e := range errs {
-			cli.Error(e.Error())
-		}
 		os.Exit(1)
 	}
+	metadata, objects := file.Metadata, file.Objects
 
 	useGenerator := make([]bool, len(availableGenerators))
 
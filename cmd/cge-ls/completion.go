@@ -12,12 +12,17 @@ import (
 )
 
 var snippets = map[string]string{
-	"config declaration":  "config {\n\t$0\n}",
-	"event declaration":   "event ${1:event_name} {\n\t$0\n}",
-	"command declaration": "command ${1:command_name} {\n\t$0\n}",
-	"type declaration":    "type ${1:type_name} {\n\t$0\n}",
-	"enum declaration":    "enum ${1:enum_name} {\n\t$0\n}",
-	"name":                "name ${1:game_name}",
+	"config declaration":       "config {\n\t$0\n}",
+	"event declaration":        "event ${1:event_name} {\n\t$0\n}",
+	"scoped event declaration": "event(${1|private,spectator,broadcast|}) ${2:event_name} {\n\t$0\n}",
+	"command declaration":      "command ${1:command_name} {\n\t$0\n}",
+	"type declaration":         "type ${1:type_name} {\n\t$0\n}",
+	"enum declaration":         "enum ${1:enum_name} {\n\t$0\n}",
+	"union declaration":        "union ${1:union_name}(${2:type}) {\n\t$0\n}",
+	"const declaration":        "const ${1:constant_name}: ${2:type} = ${3:value}",
+	"deprecated":               "@deprecated(\"${1:reason}\")",
+	"name":                     "name ${1:game_name}",
+	"import":                   "import \"${1:file.cge}\"",
 }
 
 func init() {
@@ -30,11 +35,11 @@ func init() {
 }
 
 var keywords = []string{
-	"event", "command", "type", "enum", "name", "version",
+	"event", "command", "type", "enum", "union", "const", "extends", "name", "version", "import", "true", "false",
 }
 
 var types = []string{
-	"string", "bool", "int", "int32", "int64", "float", "float32", "float64", "list", "map",
+	"string", "bool", "int", "int32", "int64", "uint", "uint32", "uint64", "float", "float32", "float64", "bytes", "timestamp", "duration", "uuid", "list", "map",
 }
 
 var completionSplitRegex = regexp.MustCompile(`[ <>:,]`)
@@ -83,16 +88,30 @@ func (d *Document) getCompletions(item string, line int) []protocol.CompletionIt
 	}
 
 	for _, o := range d.objects {
-		if (o.Type == cge.TYPE || o.Type == cge.ENUM) && strings.HasPrefix(o.Name.Lexeme, item) {
+		if (o.Type == cge.TYPE || o.Type == cge.ALIAS || o.Type == cge.ENUM || o.Type == cge.UNION) && strings.HasPrefix(o.Name.Lexeme, item) {
 			detail := o.Name.Lexeme
 			if o.Type == cge.ENUM {
 				detail = "enum " + detail
+			} else if o.Type == cge.UNION {
+				detail = "union " + detail
 			} else {
 				detail = "type " + detail
 			}
+			if len(o.TypeParameters) > 0 {
+				names := make([]string, len(o.TypeParameters))
+				for i, t := range o.TypeParameters {
+					names[i] = t.Lexeme
+				}
+				detail += "<" + strings.Join(names, ", ") + ">"
+			}
+			var tags []protocol.CompletionItemTag
+			if o.Deprecated != nil {
+				tags = append(tags, protocol.CompletionItemTagDeprecated)
+			}
 			completions = append(completions, protocol.CompletionItem{
 				Label:         o.Name.Lexeme,
 				Kind:          &classCompletionType,
+				Tags:          tags,
 				Detail:        &detail,
 				Documentation: strings.Join(o.Comments, "\n"),
 			})
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	protocol "github.com/tliron/glsp/protocol_3_16"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+// deprecationDiagnostics returns a diagnostic with the deprecated tag for every usage of a deprecated type or enum value in filename.
+func deprecationDiagnostics(objects []cge.Object, filename string) []protocol.Diagnostic {
+	types := make(map[string]cge.Object)
+	for _, o := range objects {
+		switch o.Type {
+		case cge.TYPE, cge.ENUM, cge.UNION, cge.ALIAS:
+			types[o.Name.Lexeme] = o
+		}
+	}
+
+	diagnostics := make([]protocol.Diagnostic, 0)
+	add := func(token cge.Token, deprecation *cge.Deprecation) {
+		if token.File != filename {
+			return
+		}
+		message := fmt.Sprintf("'%s' is deprecated.", token.Lexeme)
+		if deprecation.Reason != "" {
+			message = fmt.Sprintf("'%s' is deprecated: %s", token.Lexeme, deprecation.Reason)
+		}
+		severity := protocol.DiagnosticSeverityHint
+		diagnostics = append(diagnostics, protocol.Diagnostic{
+			Range: protocol.Range{
+				Start: protocol.Position{
+					Line:      uint32(token.Line),
+					Character: uint32(token.Column),
+				},
+				End: protocol.Position{
+					Line:      uint32(token.Line),
+					Character: uint32(token.Column + len(token.Lexeme)),
+				},
+			},
+			Severity: &severity,
+			Message:  message,
+			Tags:     []protocol.DiagnosticTag{protocol.DiagnosticTagDeprecated},
+		})
+	}
+
+	var checkType func(propertyType *cge.PropertyType)
+	checkType = func(propertyType *cge.PropertyType) {
+		if propertyType == nil {
+			return
+		}
+		if o, ok := types[propertyType.Token.Lexeme]; ok && propertyType.Token.Type == cge.IDENTIFIER && o.Deprecated != nil {
+			add(propertyType.Token, o.Deprecated)
+		}
+		checkType(propertyType.Key)
+		checkType(propertyType.Generic)
+		for _, a := range propertyType.TypeArguments {
+			checkType(a)
+		}
+	}
+
+	checkLiteral := func(literal *cge.Literal, propertyType *cge.PropertyType) {
+		if literal == nil || literal.Token.Type != cge.IDENTIFIER {
+			return
+		}
+		enum, ok := types[propertyType.Token.Lexeme]
+		for ok && enum.Type == cge.ALIAS {
+			enum, ok = types[enum.ValueType.Token.Lexeme]
+		}
+		if !ok || enum.Type != cge.ENUM {
+			return
+		}
+		for _, v := range enum.Properties {
+			if v.Name == literal.Value && v.Deprecated != nil {
+				add(literal.Token, v.Deprecated)
+			}
+		}
+	}
+
+	for _, o := range objects {
+		checkType(o.Extends)
+		switch o.Type {
+		case cge.CONST:
+			checkType(o.ValueType)
+			checkLiteral(o.Value, o.ValueType)
+		case cge.ALIAS:
+			checkType(o.ValueType)
+		case cge.CONFIG, cge.COMMAND, cge.EVENT, cge.TYPE:
+			for _, p := range o.Properties {
+				checkType(p.Type)
+				checkLiteral(p.Default, p.Type)
+			}
+		}
+	}
+
+	return diagnostics
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"net/url"
+	"path/filepath"
 	"sync"
 
 	"github.com/tliron/glsp"
@@ -29,35 +31,80 @@ func (d *Document) validate(notify glsp.NotifyFunc) {
 
 	defer d.sendDiagnostics(notify)
 
-	severityError := protocol.DiagnosticSeverityError
-
 	d.diagnostics = d.diagnostics[:0]
 
-	_, objects, errs := cge.Parse(bytes.NewBufferString(d.content), version)
+	filename := d.filename()
+	file, errs := cge.ParseAST(bytes.NewBufferString(d.content), filename, version)
+	if file != nil {
+		for _, warning := range file.Warnings {
+			if warning.Range.Start.File == filename {
+				d.diagnostics = append(d.diagnostics, protocolDiagnostic(warning, d.uri, filename))
+			}
+		}
+	}
 	if len(errs) > 0 {
 		for _, err := range errs {
-			if e, ok := err.(cge.ParseError); ok {
-				d.diagnostics = append(d.diagnostics, protocol.Diagnostic{
-					Range: protocol.Range{
-						Start: protocol.Position{
-							Line:      uint32(e.Token.Line),
-							Character: uint32(e.Token.Column),
-						},
-						End: protocol.Position{
-							Line:      uint32(e.Token.Line),
-							Character: uint32(e.Token.Column + len(e.Token.Lexeme)),
-						},
-					},
-					Severity: &severityError,
-					Message:  e.Message,
-				})
-			} else {
+			diagnostic := cge.AsDiagnostic(err)
+			if diagnostic.Code == cge.CodeUnknown || diagnostic.Range.Start.File != filename {
 				logging.GetLogger(name).Errorf("Failed to parse '%s': %s", d.uri, err)
+				continue
 			}
+			d.diagnostics = append(d.diagnostics, protocolDiagnostic(diagnostic, d.uri, filename))
 		}
 		return
 	}
-	d.objects = objects
+	d.objects = file.Objects
+	d.diagnostics = append(d.diagnostics, deprecationDiagnostics(file.Objects, filename)...)
+}
+
+// protocolDiagnostic converts a CGE diagnostic into an LSP diagnostic.
+// Related locations are only included if they are in the same file as the document.
+func protocolDiagnostic(diagnostic cge.Diagnostic, uri protocol.DocumentUri, filename string) protocol.Diagnostic {
+	severity := protocol.DiagnosticSeverityError
+	if diagnostic.Severity == cge.SeverityWarning {
+		severity = protocol.DiagnosticSeverityWarning
+	}
+	var related []protocol.DiagnosticRelatedInformation
+	for _, r := range diagnostic.Related {
+		if r.Range.Start.File != filename {
+			continue
+		}
+		related = append(related, protocol.DiagnosticRelatedInformation{
+			Location: protocol.Location{
+				URI:   uri,
+				Range: protocolRange(r.Range),
+			},
+			Message: r.Message,
+		})
+	}
+	return protocol.Diagnostic{
+		Range:              protocolRange(diagnostic.Range),
+		Severity:           &severity,
+		Code:               &protocol.IntegerOrString{Value: string(diagnostic.Code)},
+		Message:            diagnostic.Message,
+		RelatedInformation: related,
+	}
+}
+
+func protocolRange(r cge.Range) protocol.Range {
+	return protocol.Range{
+		Start: protocol.Position{
+			Line:      uint32(r.Start.Line),
+			Character: uint32(r.Start.Column),
+		},
+		End: protocol.Position{
+			Line:      uint32(r.End.Line),
+			Character: uint32(r.End.Column),
+		},
+	}
+}
+
+func (d *Document) filename() string {
+	u, err := url.Parse(d.uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
 }
 
 func (d *Document) sendDiagnostics(notify glsp.NotifyFunc) {
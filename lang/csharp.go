@@ -2,9 +2,11 @@ package lang
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/code-game-project/cg-gen-events/cge"
@@ -12,6 +14,19 @@ import (
 
 type CSharp struct {
 	builder strings.Builder
+	objects []cge.Object
+	unions  map[string][]string
+	aliases map[string]cge.Object
+	// system is true if any type of the System namespace is used.
+	system bool
+	// durations is true if any property requires the DurationConverter.
+	durations bool
+	// validation is true if any class has a generated Validate method.
+	validation bool
+	// deprecated is true if any declaration, property or enum value has an Obsolete attribute.
+	deprecated bool
+	// globalization is true if any integer enum converter is generated.
+	globalization bool
 }
 
 func (c *CSharp) Generate(metadata cge.Metadata, objects []cge.Object, dir string) error {
@@ -21,9 +36,19 @@ func (c *CSharp) Generate(metadata cge.Metadata, objects []cge.Object, dir strin
 	}
 
 	c.builder = strings.Builder{}
+	c.objects = objects
+	c.unions = unionsByMember(objects)
+	c.aliases = aliasesByName(objects)
+	c.system = false
+	c.durations = false
+	c.validation = false
+	c.deprecated = false
+	c.globalization = false
 
 	needsUsing := false
+	needsJSONUsing := false
 
+	constants := make([]cge.Object, 0)
 	for _, object := range objects {
 		switch object.Type {
 		case cge.CONFIG:
@@ -37,10 +62,24 @@ func (c *CSharp) Generate(metadata cge.Metadata, objects []cge.Object, dir strin
 		case cge.TYPE:
 			c.generateType(object)
 		case cge.ENUM:
+			needsJSONUsing = true
+			c.globalization = c.globalization || object.IsIntEnum()
 			c.generateEnum(object)
+		case cge.UNION:
+			needsJSONUsing = true
+			c.generateUnion(object)
+		case cge.CONST:
+			constants = append(constants, object)
 		}
 	}
 
+	c.generateConstants(cge.Fingerprint(metadata, objects), constants)
+
+	if c.durations {
+		needsJSONUsing = true
+		c.generateDurationConverter()
+	}
+
 	if len(metadata.Comments) > 0 {
 		for _, c := range metadata.Comments {
 			file.WriteString("// " + c + "\n")
@@ -48,13 +87,43 @@ func (c *CSharp) Generate(metadata cge.Metadata, objects []cge.Object, dir strin
 	}
 	fmt.Fprintf(file, "namespace %s;\n", snakeToPascal(metadata.Name))
 
-	fmt.Fprintf(file, "\nusing System.Text.Json.Serialization;\n")
+	usings := []string{"System.Text.Json.Serialization"}
+	if needsJSONUsing {
+		usings = append(usings, "System", "System.Text.Json")
+	} else if c.system {
+		usings = append(usings, "System")
+	}
+	if c.validation {
+		usings = append(usings, "System.ComponentModel.DataAnnotations", "System.Linq", "System.Text.RegularExpressions")
+	}
+	if c.globalization {
+		usings = append(usings, "System.Globalization")
+	}
+	sort.Strings(usings)
+	file.WriteString("\n")
+	for _, u := range usings {
+		fmt.Fprintf(file, "using %s;\n", u)
+	}
 
 	if needsUsing {
 		fmt.Fprintf(file, "using CodeGame.Client;\n")
 	}
 
-	file.WriteString("\n#nullable disable warnings\n")
+	if len(c.aliases) > 0 {
+		file.WriteString("\n")
+		for _, object := range objects {
+			if object.Type == cge.ALIAS {
+				c.generateAlias(object, file)
+			}
+		}
+	}
+
+	// Optional properties are annotated with "?", which requires a nullable annotation context.
+	file.WriteString("\n#nullable enable annotations\n#nullable disable warnings\n")
+	if c.deprecated {
+		// Generated properties, converters and event handlers reference obsolete members, which must not cause warnings in the generated file.
+		file.WriteString("#pragma warning disable CS0618\n")
+	}
 
 	file.WriteString(c.builder.String())
 
@@ -67,70 +136,313 @@ func (c *CSharp) Generate(metadata cge.Metadata, objects []cge.Object, dir strin
 	return nil
 }
 
+// generateConstants generates the Constants class with the schema fingerprint and all constants.
+func (c *CSharp) generateConstants(fingerprint string, constants []cge.Object) {
+	c.builder.WriteString("\npublic static class Constants\n{\n")
+	c.generateComments("    ", fingerprintComments, nil)
+	c.builder.WriteString(fmt.Sprintf("    public const string SchemaFingerprint = \"%s\";\n", fingerprint))
+	for _, constant := range constants {
+		c.builder.WriteString("\n")
+		c.generateComments("    ", constant.Comments, constant.Deprecated)
+		c.builder.WriteString(fmt.Sprintf("    public const %s %s = %s;\n", c.csType(constant.ValueType.Token.Type, constant.ValueType.Token.Lexeme, nil, nil, nil), snakeToPascal(constant.Name.Lexeme), c.csLiteral(*constant.Value, constant.ValueType)))
+	}
+	c.builder.WriteString("}\n")
+}
+
+// generateAlias writes a using alias directive. C# doesn't allow comments to be attached to using directives,
+// which is why they are written as regular comments.
+func (c *CSharp) generateAlias(object cge.Object, writer io.Writer) {
+	for _, comment := range object.Comments {
+		fmt.Fprintf(writer, "// %s\n", comment)
+	}
+	if object.Deprecated != nil {
+		fmt.Fprintf(writer, "// Deprecated: %s\n", deprecationReason(object.Deprecated))
+	}
+	fmt.Fprintf(writer, "using %s = %s;\n", snakeToPascal(object.Name.Lexeme), c.csQualifiedType(object.ValueType))
+}
+
 func (c *CSharp) generateConfig(object cge.Object) {
 	c.builder.WriteString("\n")
-	c.generateComments("", object.Comments)
+	c.generateComments("", object.Comments, object.Deprecated)
 	c.builder.WriteString("public class GameConfig\n{\n")
 
 	c.generateProperties(object.Properties)
+	c.generateValidate(object)
 
 	c.builder.WriteString("}\n")
 }
 
 func (c *CSharp) generateCommand(object cge.Object) {
 	c.builder.WriteString("\n")
-	c.generateComments("", object.Comments)
-	c.builder.WriteString(fmt.Sprintf("public class %sCmd : CommandData\n{\n", snakeToPascal(object.Name.Lexeme)))
+	c.generateComments("", object.Comments, object.Deprecated)
+	c.builder.WriteString(fmt.Sprintf("public class %sCmd : %sCommandData\n{\n", snakeToPascal(object.Name.Lexeme), c.base(object)))
 
 	c.generateProperties(object.Properties)
+	c.generateValidate(object)
 
 	c.builder.WriteString("}\n")
 }
 
 func (c *CSharp) generateEvent(object cge.Object) {
 	c.builder.WriteString("\n")
-	c.generateComments("", object.Comments)
-	c.builder.WriteString(fmt.Sprintf("public class %sEvent : EventData\n{\n", snakeToPascal(object.Name.Lexeme)))
+	c.generateComments("", object.Comments, object.Deprecated)
+	c.builder.WriteString(fmt.Sprintf("public class %sEvent : %sEventData\n{\n", snakeToPascal(object.Name.Lexeme), c.base(object)))
 
 	c.generateProperties(object.Properties)
+	c.generateValidate(object)
 
 	c.builder.WriteString("}\n")
 }
 
+// base returns the base class of object followed by ", " or an empty string if object doesn't extend a type.
+func (c *CSharp) base(object cge.Object) string {
+	if object.Extends == nil {
+		return ""
+	}
+	return snakeToPascal(object.Extends.Token.Lexeme) + ", "
+}
+
 func (c *CSharp) generateType(object cge.Object) {
+	name := snakeToPascal(object.Name.Lexeme) + typeParameterList(object.TypeParameters)
 	c.builder.WriteString("\n")
-	c.generateComments("", object.Comments)
-	c.builder.WriteString(fmt.Sprintf("public class %s\n{\n", snakeToPascal(object.Name.Lexeme)))
+	c.generateComments("", object.Comments, object.Deprecated)
+	if unions, ok := c.unions[object.Name.Lexeme]; ok {
+		names := make([]string, len(unions))
+		for i, u := range unions {
+			names[i] = snakeToPascal(u)
+		}
+		c.builder.WriteString(fmt.Sprintf("public class %s : %s%s\n{\n", name, c.base(object), strings.Join(names, ", ")))
+	} else if object.Extends != nil {
+		c.builder.WriteString(fmt.Sprintf("public class %s : %s\n{\n", name, snakeToPascal(object.Extends.Token.Lexeme)))
+	} else {
+		c.builder.WriteString(fmt.Sprintf("public class %s\n{\n", name))
+	}
 
 	c.generateProperties(object.Properties)
+	c.generateValidate(object)
 
 	c.builder.WriteString("}\n")
 }
 
 func (c *CSharp) generateEnum(object cge.Object) {
+	name := snakeToPascal(object.Name.Lexeme)
 	c.builder.WriteString("\n")
-	c.generateComments("", object.Comments)
-	c.builder.WriteString("[JsonConverter(typeof(JsonStringEnumConverter))]\n")
-	c.builder.WriteString(fmt.Sprintf("public enum %s\n{\n", snakeToPascal(object.Name.Lexeme)))
+	c.generateComments("", object.Comments, object.Deprecated)
+	c.builder.WriteString(fmt.Sprintf("[JsonConverter(typeof(%sConverter))]\n", name))
+	values := object.EnumValues()
+	if object.IsIntEnum() {
+		c.builder.WriteString(fmt.Sprintf("public enum %s : %s\n{\n", name, c.csType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil)))
+		for i, property := range object.Properties {
+			c.generateComments("    ", property.Comments, property.Deprecated)
+			c.builder.WriteString(fmt.Sprintf("    %s = %s,\n", snakeToPascal(property.Name), values[i]))
+		}
+		c.builder.WriteString("}\n")
+		c.generateIntEnumConverter(object)
+		return
+	}
+
+	c.builder.WriteString(fmt.Sprintf("public enum %s\n{\n", name))
 
 	for _, property := range object.Properties {
-		c.generateComments("    ", property.Comments)
-		c.builder.WriteString(fmt.Sprintf("    [JsonPropertyName(\"%s\")]\n", property.Name))
+		c.generateComments("    ", property.Comments, property.Deprecated)
 		c.builder.WriteString(fmt.Sprintf("    %s,\n", snakeToPascal(property.Name)))
 	}
 
 	c.builder.WriteString("}\n")
+
+	c.generateEnumConverter(object)
+}
+
+// generateEnumConverter generates a converter which encodes the values of a string enum as their explicit or implicit string values.
+// JsonStringEnumConverter can't be used, because it ignores JsonPropertyName attributes on enum members.
+func (c *CSharp) generateEnumConverter(object cge.Object) {
+	name := snakeToPascal(object.Name.Lexeme)
+	values := object.EnumValues()
+
+	c.builder.WriteString(fmt.Sprintf("\npublic class %sConverter : JsonConverter<%s>\n{\n", name, name))
+
+	c.builder.WriteString(fmt.Sprintf("    public override %s Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString("        var value = reader.GetString();\n")
+	c.builder.WriteString("        return value switch\n        {\n")
+	for i, property := range object.Properties {
+		c.builder.WriteString(fmt.Sprintf("            %s => %s.%s,\n", quoteString(values[i]), name, snakeToPascal(property.Name)))
+	}
+	c.builder.WriteString(fmt.Sprintf("            _ => throw new JsonException($\"Unknown %s value: {value}\"),\n", object.Name.Lexeme))
+	c.builder.WriteString("        };\n    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override void Write(Utf8JsonWriter writer, %s value, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString("        writer.WriteStringValue(ToValue(value));\n")
+	c.builder.WriteString("    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override %s ReadAsPropertyName(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString("        return Read(ref reader, typeToConvert, options);\n")
+	c.builder.WriteString("    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override void WriteAsPropertyName(Utf8JsonWriter writer, %s value, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString("        writer.WritePropertyName(ToValue(value));\n")
+	c.builder.WriteString("    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    private static string ToValue(%s value)\n    {\n", name))
+	c.builder.WriteString("        return value switch\n        {\n")
+	for i, property := range object.Properties {
+		c.builder.WriteString(fmt.Sprintf("            %s.%s => %s,\n", name, snakeToPascal(property.Name), quoteString(values[i])))
+	}
+	c.builder.WriteString(fmt.Sprintf("            _ => throw new JsonException($\"Unknown %s value: {value}\"),\n", object.Name.Lexeme))
+	c.builder.WriteString("        };\n    }\n")
+
+	c.builder.WriteString("}\n")
+}
+
+// generateIntEnumConverter generates a converter which encodes the values of an integer enum as numbers.
+// Map keys are encoded as the decimal string of the number like in all other languages instead of the name of the enum member.
+func (c *CSharp) generateIntEnumConverter(object cge.Object) {
+	name := snakeToPascal(object.Name.Lexeme)
+	valueType := c.csType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil)
+	getter := "GetInt32"
+	if object.ValueType.Token.Type == cge.INT64 {
+		getter = "GetInt64"
+	}
+
+	c.builder.WriteString(fmt.Sprintf("\npublic class %sConverter : JsonConverter<%s>\n{\n", name, name))
+
+	c.builder.WriteString(fmt.Sprintf("    public override %s Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString(fmt.Sprintf("        return (%s)reader.%s();\n", name, getter))
+	c.builder.WriteString("    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override void Write(Utf8JsonWriter writer, %s value, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString(fmt.Sprintf("        writer.WriteNumberValue((%s)value);\n", valueType))
+	c.builder.WriteString("    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override %s ReadAsPropertyName(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString(fmt.Sprintf("        return (%s)%s.Parse(reader.GetString()!, CultureInfo.InvariantCulture);\n", name, valueType))
+	c.builder.WriteString("    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override void WriteAsPropertyName(Utf8JsonWriter writer, %s value, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString(fmt.Sprintf("        writer.WritePropertyName(((%s)value).ToString(CultureInfo.InvariantCulture));\n", valueType))
+	c.builder.WriteString("    }\n")
+
+	c.builder.WriteString("}\n")
+}
+
+func (c *CSharp) generateUnion(object cge.Object) {
+	name := snakeToPascal(object.Name.Lexeme)
+	discriminator := object.Discriminator.Lexeme
+
+	c.builder.WriteString("\n")
+	c.generateComments("", object.Comments, object.Deprecated)
+	c.builder.WriteString(fmt.Sprintf("[JsonConverter(typeof(%sConverter))]\n", name))
+	c.builder.WriteString(fmt.Sprintf("public interface %s\n{\n}\n", name))
+
+	c.builder.WriteString(fmt.Sprintf("\npublic class %sConverter : JsonConverter<%s>\n{\n", name, name))
+
+	c.builder.WriteString(fmt.Sprintf("    public override %s Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString("        using var document = JsonDocument.ParseValue(ref reader);\n")
+	c.builder.WriteString(fmt.Sprintf("        if (!document.RootElement.TryGetProperty(\"%s\", out var discriminator))\n", discriminator))
+	c.builder.WriteString(fmt.Sprintf("            throw new JsonException(\"Missing discriminator '%s' in %s.\");\n", discriminator, object.Name.Lexeme))
+	c.builder.WriteString("        return discriminator.GetString() switch\n        {\n")
+	for _, member := range object.Properties {
+		c.builder.WriteString(fmt.Sprintf("            \"%s\" => document.RootElement.Deserialize<%s>(options),\n", member.Name, snakeToPascal(member.Name)))
+	}
+	c.builder.WriteString(fmt.Sprintf("            _ => throw new JsonException($\"Unknown %s %s: {discriminator.GetString()}\"),\n", object.Name.Lexeme, discriminator))
+	c.builder.WriteString("        };\n    }\n\n")
+
+	c.builder.WriteString(fmt.Sprintf("    public override void Write(Utf8JsonWriter writer, %s value, JsonSerializerOptions options)\n    {\n", name))
+	c.builder.WriteString("        var discriminator = value switch\n        {\n")
+	for _, member := range object.Properties {
+		c.builder.WriteString(fmt.Sprintf("            %s => \"%s\",\n", snakeToPascal(member.Name), member.Name))
+	}
+	c.builder.WriteString(fmt.Sprintf("            _ => throw new JsonException($\"Unknown %s type: {value.GetType()}\"),\n", object.Name.Lexeme))
+	c.builder.WriteString("        };\n")
+	c.builder.WriteString("        writer.WriteStartObject();\n")
+	c.builder.WriteString(fmt.Sprintf("        writer.WriteString(\"%s\", discriminator);\n", discriminator))
+	c.builder.WriteString("        foreach (var property in JsonSerializer.SerializeToElement(value, value.GetType(), options).EnumerateObject())\n")
+	c.builder.WriteString("            property.WriteTo(writer);\n")
+	c.builder.WriteString("        writer.WriteEndObject();\n")
+	c.builder.WriteString("    }\n")
+
+	c.builder.WriteString("}\n")
 }
 
 func (c *CSharp) generateProperties(properties []cge.Property) {
 	for _, property := range properties {
-		c.generateComments("    ", property.Comments)
+		c.generateComments("    ", property.Comments, property.Deprecated)
 		c.builder.WriteString(fmt.Sprintf("    [JsonPropertyName(\"%s\")]\n", property.Name))
-		c.builder.WriteString(fmt.Sprintf("    public %s %s { get; set; }\n", c.csType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic), snakeToPascal(property.Name)))
+		if resolveAlias(c.aliases, property.Type).Token.Type == cge.DURATION {
+			c.durations = true
+			c.builder.WriteString("    [JsonConverter(typeof(DurationConverter))]\n")
+		}
+		csType := c.csType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic, property.Type.Key, property.Type.TypeArguments)
+		if property.Optional {
+			c.builder.WriteString("    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)]\n")
+			csType += "?"
+		}
+		if property.Default != nil {
+			c.builder.WriteString(fmt.Sprintf("    public %s %s { get; set; } = %s;\n", csType, snakeToPascal(property.Name), c.csLiteral(*property.Default, property.Type)))
+		} else {
+			c.builder.WriteString(fmt.Sprintf("    public %s %s { get; set; }\n", csType, snakeToPascal(property.Name)))
+		}
 	}
 }
 
-func (c *CSharp) generateComments(indent string, comments []string) {
+// generateValidate generates a Validate method which throws a ValidationException if a property violates its constraints.
+func (c *CSharp) generateValidate(object cge.Object) {
+	if !hasConstraints(object.Properties) {
+		return
+	}
+	c.validation = true
+
+	c.builder.WriteString("\n")
+	if baseHasConstraints(c.objects, object) {
+		c.builder.WriteString("    public override void Validate()\n    {\n")
+		c.builder.WriteString("        base.Validate();\n")
+	} else {
+		c.builder.WriteString("    public virtual void Validate()\n    {\n")
+	}
+	for _, property := range object.Properties {
+		field := snakeToPascal(property.Name)
+		guard := ""
+		if property.Optional {
+			guard = field + " != null && "
+		}
+		for _, constraint := range property.Constraints {
+			var condition string
+			switch constraint.Name.Lexeme {
+			case "min":
+				condition = fmt.Sprintf("%s < %s", field, c.csLiteral(constraint.Value, property.Type))
+			case "max":
+				condition = fmt.Sprintf("%s > %s", field, c.csLiteral(constraint.Value, property.Type))
+			case "min_len":
+				condition = fmt.Sprintf("%s.EnumerateRunes().Count() < %s", field, constraint.Value.Value)
+			case "max_len":
+				condition = fmt.Sprintf("%s.EnumerateRunes().Count() > %s", field, constraint.Value.Value)
+			case "pattern":
+				condition = fmt.Sprintf("!Regex.IsMatch(%s, %s)", field, quoteString(constraint.Value.Value))
+			case "min_items":
+				condition = fmt.Sprintf("%s.Count < %s", field, constraint.Value.Value)
+			case "max_items":
+				condition = fmt.Sprintf("%s.Count > %s", field, constraint.Value.Value)
+			}
+			c.builder.WriteString(fmt.Sprintf("        if (%s%s)\n", guard, condition))
+			c.builder.WriteString(fmt.Sprintf("            throw new ValidationException(%s);\n", quoteString(constraintMessage(property, constraint))))
+		}
+	}
+	c.builder.WriteString("    }\n")
+}
+
+// generateDurationConverter generates a converter which encodes TimeSpan values as nanoseconds.
+func (c *CSharp) generateDurationConverter() {
+	c.builder.WriteString("\npublic class DurationConverter : JsonConverter<TimeSpan>\n{\n")
+	c.builder.WriteString("    public override TimeSpan Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)\n    {\n")
+	c.builder.WriteString("        return TimeSpan.FromTicks(reader.GetInt64() / 100);\n")
+	c.builder.WriteString("    }\n\n")
+	c.builder.WriteString("    public override void Write(Utf8JsonWriter writer, TimeSpan value, JsonSerializerOptions options)\n    {\n")
+	c.builder.WriteString("        writer.WriteNumberValue(value.Ticks * 100);\n")
+	c.builder.WriteString("    }\n")
+	c.builder.WriteString("}\n")
+}
+
+// generateComments generates a documentation comment and an Obsolete attribute if deprecation is not nil.
+func (c *CSharp) generateComments(indent string, comments []string, deprecation *cge.Deprecation) {
 	if len(comments) > 0 {
 		c.builder.WriteString(indent + "/// <summary>\n")
 		for _, comment := range comments {
@@ -138,9 +450,14 @@ func (c *CSharp) generateComments(indent string, comments []string) {
 		}
 		c.builder.WriteString(indent + "/// </summary>\n")
 	}
+	if deprecation != nil {
+		c.system = true
+		c.deprecated = true
+		c.builder.WriteString(fmt.Sprintf("%s[Obsolete(%s)]\n", indent, quoteString(deprecationReason(deprecation))))
+	}
 }
 
-func (c *CSharp) csType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType) string {
+func (c *CSharp) csType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType, key *cge.PropertyType, typeArguments []*cge.PropertyType) string {
 	switch tokenType {
 	case cge.STRING:
 		return "string"
@@ -154,12 +471,127 @@ func (c *CSharp) csType(tokenType cge.TokenType, lexeme string, generic *cge.Pro
 		return "float"
 	case cge.FLOAT64:
 		return "double"
+	case cge.UINT32:
+		return "uint"
+	case cge.UINT64:
+		return "ulong"
+	case cge.BYTES:
+		return "byte[]"
+	case cge.TIMESTAMP:
+		c.system = true
+		return "DateTimeOffset"
+	case cge.DURATION:
+		c.system = true
+		return "TimeSpan"
+	case cge.UUID:
+		c.system = true
+		return "Guid"
 	case cge.LIST:
-		return "List<" + c.csType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + ">"
+		return "List<" + c.csElementType(generic) + ">"
 	case cge.MAP:
-		return "Dictionary<string, " + c.csType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + ">"
+		keyType := "string"
+		if key != nil {
+			keyType = c.csType(key.Token.Type, key.Token.Lexeme, nil, nil, nil)
+		}
+		return "Dictionary<" + keyType + ", " + c.csElementType(generic) + ">"
 	case cge.IDENTIFIER:
+		if len(typeArguments) > 0 {
+			arguments := make([]string, len(typeArguments))
+			for i, a := range typeArguments {
+				arguments[i] = c.csElementType(a)
+			}
+			return snakeToPascal(lexeme) + "<" + strings.Join(arguments, ", ") + ">"
+		}
+		return snakeToPascal(lexeme)
+	case cge.TYPE_PARAMETER:
 		return snakeToPascal(lexeme)
 	}
 	return "object"
 }
+
+// csElementType returns the type of the elements of a list or map or of a type argument.
+// Durations are represented as nanoseconds, because the DurationConverter cannot be applied to elements.
+func (c *CSharp) csElementType(generic *cge.PropertyType) string {
+	if resolveAlias(c.aliases, generic).Token.Type == cge.DURATION {
+		return "long"
+	}
+	return c.csType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments)
+}
+
+// csQualifiedType returns the fully qualified name of propertyType with all aliases resolved as required by using alias directives.
+func (c *CSharp) csQualifiedType(propertyType *cge.PropertyType) string {
+	propertyType = resolveAlias(c.aliases, propertyType)
+	switch propertyType.Token.Type {
+	case cge.STRING:
+		return "System.String"
+	case cge.BOOL:
+		return "System.Boolean"
+	case cge.INT32:
+		return "System.Int32"
+	case cge.INT64:
+		return "System.Int64"
+	case cge.FLOAT32:
+		return "System.Single"
+	case cge.FLOAT64:
+		return "System.Double"
+	case cge.UINT32:
+		return "System.UInt32"
+	case cge.UINT64:
+		return "System.UInt64"
+	case cge.BYTES:
+		return "System.Byte[]"
+	case cge.TIMESTAMP:
+		return "System.DateTimeOffset"
+	case cge.DURATION:
+		return "System.TimeSpan"
+	case cge.UUID:
+		return "System.Guid"
+	case cge.LIST:
+		return "System.Collections.Generic.List<" + c.csQualifiedElementType(propertyType.Generic) + ">"
+	case cge.MAP:
+		keyType := "System.String"
+		if propertyType.Key != nil {
+			keyType = c.csQualifiedType(propertyType.Key)
+		}
+		return "System.Collections.Generic.Dictionary<" + keyType + ", " + c.csQualifiedElementType(propertyType.Generic) + ">"
+	case cge.IDENTIFIER:
+		if len(propertyType.TypeArguments) > 0 {
+			arguments := make([]string, len(propertyType.TypeArguments))
+			for i, a := range propertyType.TypeArguments {
+				arguments[i] = c.csQualifiedElementType(a)
+			}
+			return snakeToPascal(propertyType.Token.Lexeme) + "<" + strings.Join(arguments, ", ") + ">"
+		}
+		return snakeToPascal(propertyType.Token.Lexeme)
+	}
+	return "System.Object"
+}
+
+func (c *CSharp) csQualifiedElementType(generic *cge.PropertyType) string {
+	if resolveAlias(c.aliases, generic).Token.Type == cge.DURATION {
+		return "System.Int64"
+	}
+	return c.csQualifiedType(generic)
+}
+
+func (c *CSharp) csLiteral(literal cge.Literal, propertyType *cge.PropertyType) string {
+	propertyType = resolveAlias(c.aliases, propertyType)
+	switch literal.Token.Type {
+	case cge.STRING_LITERAL:
+		return quoteString(literal.Value)
+	case cge.NUMBER:
+		switch propertyType.Token.Type {
+		case cge.INT64:
+			return literal.Value + "L"
+		case cge.FLOAT32:
+			return literal.Value + "f"
+		case cge.FLOAT64:
+			return literal.Value + "d"
+		}
+	case cge.IDENTIFIER:
+		return snakeToPascal(propertyType.Token.Lexeme) + "." + snakeToPascal(literal.Value)
+	case cge.OPEN_SQUARE, cge.OPEN_CURLY:
+		return "new()"
+	}
+	return literal.Value
+}
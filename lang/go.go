@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/code-game-project/cg-gen-events/cge"
@@ -14,6 +16,9 @@ import (
 
 type Go struct {
 	builder strings.Builder
+	imports map[string]struct{}
+	objects []cge.Object
+	aliases map[string]cge.Object
 }
 
 func (g *Go) Generate(metadata cge.Metadata, objects []cge.Object, dir string) error {
@@ -23,25 +28,50 @@ func (g *Go) Generate(metadata cge.Metadata, objects []cge.Object, dir string) e
 	}
 
 	g.builder = strings.Builder{}
+	g.imports = make(map[string]struct{})
+	g.objects = objects
+	g.aliases = aliasesByName(objects)
+
+	g.generateFingerprint(metadata, objects)
 
 	needsImport := false
+	responses := false
+	scopes := make(map[string][]string)
 
 	for _, object := range objects {
 		if object.Type == cge.CONFIG {
 			g.generateConfig(object)
 		} else if object.Type == cge.COMMAND {
 			needsImport = true
+			responses = responses || len(object.Responses) > 0
 			g.generateCommand(object)
 		} else if object.Type == cge.EVENT {
 			needsImport = true
+			if object.Scope.Lexeme != "" {
+				scopes[object.Scope.Lexeme] = append(scopes[object.Scope.Lexeme], object.Name.Lexeme)
+			}
 			g.generateEvent(object)
 		} else if object.Type == cge.TYPE {
 			g.generateType(object)
+		} else if object.Type == cge.ALIAS {
+			g.generateAlias(object)
 		} else if object.Type == cge.ENUM {
 			g.generateEnum(object)
+		} else if object.Type == cge.UNION {
+			g.generateUnion(object)
+		} else if object.Type == cge.CONST {
+			g.generateConstant(object)
 		}
 	}
 
+	if responses {
+		g.generateCommandSocket()
+	}
+
+	if len(scopes) > 0 {
+		g.generateEventScopes(scopes)
+	}
+
 	if len(metadata.Comments) > 0 {
 		file.WriteString("/*\n")
 		for _, c := range metadata.Comments {
@@ -51,8 +81,23 @@ func (g *Go) Generate(metadata cge.Metadata, objects []cge.Object, dir string) e
 	}
 	fmt.Fprintf(file, "package %s\n", detectPackageName(dir, snakeToOneWord(metadata.Name)))
 
-	if needsImport {
+	imports := make([]string, 0, len(g.imports))
+	for i := range g.imports {
+		imports = append(imports, i)
+	}
+	sort.Strings(imports)
+
+	if len(imports) == 0 && needsImport {
 		fmt.Fprintf(file, "\nimport \"%s/cg\"\n", detectImportPath(dir, "github.com/code-game-project/go-client"))
+	} else if len(imports) > 0 {
+		file.WriteString("\nimport (\n")
+		for _, i := range imports {
+			fmt.Fprintf(file, "\t\"%s\"\n", i)
+		}
+		if needsImport {
+			fmt.Fprintf(file, "\n\t\"%s/cg\"\n", detectImportPath(dir, "github.com/code-game-project/go-client"))
+		}
+		file.WriteString(")\n")
 	}
 
 	file.WriteString(g.builder.String())
@@ -66,76 +111,404 @@ func (g *Go) Generate(metadata cge.Metadata, objects []cge.Object, dir string) e
 	return nil
 }
 
+func (g *Go) generateFingerprint(metadata cge.Metadata, objects []cge.Object) {
+	g.builder.WriteString("\n")
+	g.generateComments("", fingerprintComments, nil)
+	g.builder.WriteString(fmt.Sprintf("const SchemaFingerprint = \"%s\"\n", cge.Fingerprint(metadata, objects)))
+}
+
+func (g *Go) generateConstant(object cge.Object) {
+	g.builder.WriteString("\n")
+	g.generateComments("", object.Comments, object.Deprecated)
+	g.builder.WriteString(fmt.Sprintf("const %s %s = %s\n", snakeToPascal(object.Name.Lexeme), g.goType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil), g.goLiteral(*object.Value, object.ValueType)))
+}
+
 func (g *Go) generateConfig(object cge.Object) {
 	g.builder.WriteString("\n")
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString("type GameConfig struct {\n")
 
 	g.generateProperties(object.Properties)
 
 	g.builder.WriteString("}\n")
+
+	g.generateDefaults("GameConfig", object.Properties)
+	g.generateValidate("GameConfig", object)
 }
 
 func (g *Go) generateCommand(object cge.Object) {
 	g.builder.WriteString("\n")
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("const %sCmd cg.CommandName = \"%s\"\n\n", snakeToPascal(object.Name.Lexeme), object.Name.Lexeme))
+	g.generateComments("", nil, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("type %sCmdData struct {\n", snakeToPascal(object.Name.Lexeme)))
 
+	g.generateBase(object)
 	g.generateProperties(object.Properties)
 
 	g.builder.WriteString("}\n")
+
+	g.generateDefaults(snakeToPascal(object.Name.Lexeme)+"CmdData", object.Properties)
+	g.generateValidate(snakeToPascal(object.Name.Lexeme)+"CmdData", object)
+	g.generateSendAndWait(object)
+}
+
+// generateSendAndWait generates a response type which contains one of the response events of a command
+// and a function which sends the command and waits for the response.
+func (g *Go) generateSendAndWait(object cge.Object) {
+	if len(object.Responses) == 0 {
+		return
+	}
+
+	g.imports["context"] = struct{}{}
+	g.imports["encoding/json"] = struct{}{}
+	g.imports["fmt"] = struct{}{}
+
+	name := snakeToPascal(object.Name.Lexeme)
+	eventNames := make([]string, len(object.Responses))
+	eventConstants := make([]string, len(object.Responses))
+	for i, r := range object.Responses {
+		eventNames[i] = r.Lexeme
+		eventConstants[i] = snakeToPascal(r.Lexeme) + "Event"
+	}
+
+	g.builder.WriteString(fmt.Sprintf("\n// %sResponse is the response to a %s command. Exactly one of its fields is set.\n", name, object.Name.Lexeme))
+	g.builder.WriteString(fmt.Sprintf("type %sResponse struct {\n", name))
+	for _, r := range object.Responses {
+		g.builder.WriteString(fmt.Sprintf("\t%s *%sEventData\n", snakeToPascal(r.Lexeme), snakeToPascal(r.Lexeme)))
+	}
+	g.builder.WriteString("}\n")
+
+	g.builder.WriteString(fmt.Sprintf("\n// Send%sAndWait sends a %s command and waits for a %s event.\n", name, object.Name.Lexeme, strings.Join(eventNames, " or ")))
+	g.builder.WriteString(fmt.Sprintf("func Send%sAndWait(ctx context.Context, socket CommandSocket, data %sCmdData) (%sResponse, error) {\n", name, name, name))
+	g.builder.WriteString(fmt.Sprintf("\tevent, eventData, err := socket.SendAndWait(ctx, %sCmd, data, %s)\n", name, strings.Join(eventConstants, ", ")))
+	g.builder.WriteString("\tif err != nil {\n")
+	g.builder.WriteString(fmt.Sprintf("\t\treturn %sResponse{}, err\n", name))
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString(fmt.Sprintf("\tvar response %sResponse\n", name))
+	g.builder.WriteString("\tswitch event {\n")
+	for i, r := range object.Responses {
+		g.builder.WriteString(fmt.Sprintf("\tcase %s:\n", eventConstants[i]))
+		g.builder.WriteString(fmt.Sprintf("\t\tresponse.%s = &%sEventData{}\n", snakeToPascal(r.Lexeme), snakeToPascal(r.Lexeme)))
+		g.builder.WriteString(fmt.Sprintf("\t\terr = json.Unmarshal(eventData, response.%s)\n", snakeToPascal(r.Lexeme)))
+	}
+	g.builder.WriteString("\tdefault:\n")
+	g.builder.WriteString(fmt.Sprintf("\t\terr = fmt.Errorf(\"unexpected response to %s: %%q\", event)\n", object.Name.Lexeme))
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\treturn response, err\n")
+	g.builder.WriteString("}\n")
+}
+
+// generateEventScopes generates a set of event names for every event scope which is used by at least one event.
+// Servers can use the sets to check the audience of an event.
+func (g *Go) generateEventScopes(scopes map[string][]string) {
+	for _, scope := range cge.EventScopes {
+		if len(scopes[scope]) == 0 {
+			continue
+		}
+		g.builder.WriteString(fmt.Sprintf("\n// %sEvents contains the names of all events with the scope '%s'.\n", snakeToPascal(scope), scope))
+		g.builder.WriteString(fmt.Sprintf("var %sEvents = map[cg.EventName]struct{}{\n", snakeToPascal(scope)))
+		for _, event := range scopes[scope] {
+			g.builder.WriteString(fmt.Sprintf("\t%sEvent: {},\n", snakeToPascal(event)))
+		}
+		g.builder.WriteString("}\n")
+	}
+}
+
+// generateCommandSocket generates the interface which is used by the Send...AndWait functions to communicate with the server
+// and an implementation of it, which wraps the *cg.Socket of go-client v0.9.
+// The methods of cg.Socket are only called and not matched by an interface, so that the named callback type of On doesn't have to be spelled out.
+func (g *Go) generateCommandSocket() {
+	g.builder.WriteString("\n// CommandSocket sends commands and receives the events which are sent in response.\n")
+	g.builder.WriteString("// Use NewCommandSocket to create a CommandSocket from a *cg.Socket.\n")
+	g.builder.WriteString("type CommandSocket interface {\n")
+	g.builder.WriteString("\t// SendAndWait sends a command and blocks until one of the response events is received or ctx is done.\n")
+	g.builder.WriteString("\t// It returns the name and the encoded data of the received event.\n")
+	g.builder.WriteString("\tSendAndWait(ctx context.Context, command cg.CommandName, data any, responses ...cg.EventName) (cg.EventName, json.RawMessage, error)\n")
+	g.builder.WriteString("}\n")
+
+	g.builder.WriteString("\n// NewCommandSocket returns a CommandSocket which uses socket (go-client v0.9) to send commands and receive events.\n")
+	g.builder.WriteString("// The response events are received by the event loop of socket, so SendAndWait must not be called from an event callback.\n")
+	g.builder.WriteString("func NewCommandSocket(socket *cg.Socket) CommandSocket {\n")
+	g.builder.WriteString("\treturn commandSocket{socket: socket}\n")
+	g.builder.WriteString("}\n")
+
+	g.builder.WriteString("\ntype commandSocket struct {\n")
+	g.builder.WriteString("\tsocket *cg.Socket\n")
+	g.builder.WriteString("}\n")
+
+	g.builder.WriteString("\nfunc (s commandSocket) SendAndWait(ctx context.Context, command cg.CommandName, data any, responses ...cg.EventName) (cg.EventName, json.RawMessage, error) {\n")
+	g.builder.WriteString("\treceived := make(chan cg.Event, 1)\n")
+	g.builder.WriteString("\tfor _, response := range responses {\n")
+	g.builder.WriteString("\t\tid := s.socket.On(response, func(event cg.Event) {\n")
+	g.builder.WriteString("\t\t\tselect {\n")
+	g.builder.WriteString("\t\t\tcase received <- event:\n")
+	g.builder.WriteString("\t\t\tdefault:\n")
+	g.builder.WriteString("\t\t\t}\n")
+	g.builder.WriteString("\t\t})\n")
+	g.builder.WriteString("\t\tdefer s.socket.RemoveCallback(id)\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\tif err := s.socket.Send(command, data); err != nil {\n")
+	g.builder.WriteString("\t\treturn \"\", nil, err\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\tselect {\n")
+	g.builder.WriteString("\tcase event := <-received:\n")
+	g.builder.WriteString("\t\treturn event.Name, event.Data, nil\n")
+	g.builder.WriteString("\tcase <-ctx.Done():\n")
+	g.builder.WriteString("\t\treturn \"\", nil, ctx.Err()\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("}\n")
 }
 
 func (g *Go) generateEvent(object cge.Object) {
 	g.builder.WriteString("\n")
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("const %sEvent cg.EventName = \"%s\"\n\n", snakeToPascal(object.Name.Lexeme), object.Name.Lexeme))
+	g.generateComments("", nil, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("type %sEventData struct {\n", snakeToPascal(object.Name.Lexeme)))
 
+	g.generateBase(object)
 	g.generateProperties(object.Properties)
 
 	g.builder.WriteString("}\n")
+
+	g.generateValidate(snakeToPascal(object.Name.Lexeme)+"EventData", object)
 }
 
 func (g *Go) generateType(object cge.Object) {
 	g.builder.WriteString("\n")
-	g.generateComments("", object.Comments)
-	g.builder.WriteString(fmt.Sprintf("type %s struct {\n", snakeToPascal(object.Name.Lexeme)))
+	g.generateComments("", object.Comments, object.Deprecated)
+	g.builder.WriteString(fmt.Sprintf("type %s%s struct {\n", snakeToPascal(object.Name.Lexeme), goTypeParameters(object.TypeParameters, true)))
 
+	g.generateBase(object)
 	g.generateProperties(object.Properties)
 
 	g.builder.WriteString("}\n")
+
+	g.generateValidate(snakeToPascal(object.Name.Lexeme), object)
+}
+
+func (g *Go) generateAlias(object cge.Object) {
+	g.builder.WriteString("\n")
+	g.generateComments("", object.Comments, object.Deprecated)
+	// Aliases of user defined types and timestamps are Go type aliases to keep the methods and constants of the underlying type.
+	if object.ValueType.Token.Type == cge.IDENTIFIER || object.ValueType.Token.Type == cge.TIMESTAMP {
+		g.builder.WriteString(fmt.Sprintf("type %s = %s\n", snakeToPascal(object.Name.Lexeme), g.goType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, object.ValueType.TypeArguments)))
+	} else {
+		g.builder.WriteString(fmt.Sprintf("type %s %s\n", snakeToPascal(object.Name.Lexeme), g.goType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, object.ValueType.Generic, object.ValueType.Key, object.ValueType.TypeArguments)))
+	}
 }
 
 func (g *Go) generateEnum(object cge.Object) {
 	g.builder.WriteString("\n")
-	g.generateComments("", object.Comments)
-	g.builder.WriteString(fmt.Sprintf("type %s string\n", snakeToPascal(object.Name.Lexeme)))
+	g.generateComments("", object.Comments, object.Deprecated)
+	if object.IsIntEnum() {
+		g.builder.WriteString(fmt.Sprintf("type %s %s\n", snakeToPascal(object.Name.Lexeme), g.goType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil)))
+	} else {
+		g.builder.WriteString(fmt.Sprintf("type %s string\n", snakeToPascal(object.Name.Lexeme)))
+	}
 	if len(object.Properties) > 0 {
+		values := object.EnumValues()
 		g.builder.WriteString("\nconst (\n")
-		for _, property := range object.Properties {
-			g.generateComments("\t", property.Comments)
-			g.builder.WriteString(fmt.Sprintf("%s%s %s = \"%s\"\n", snakeToPascal(object.Name.Lexeme), snakeToPascal(property.Name), snakeToPascal(object.Name.Lexeme), property.Name))
+		for i, property := range object.Properties {
+			value := values[i]
+			if !object.IsIntEnum() {
+				value = strconv.Quote(value)
+			}
+			g.generateComments("\t", property.Comments, property.Deprecated)
+			g.builder.WriteString(fmt.Sprintf("%s%s %s = %s\n", snakeToPascal(object.Name.Lexeme), snakeToPascal(property.Name), snakeToPascal(object.Name.Lexeme), value))
 		}
 		g.builder.WriteString(")\n")
 	}
 }
 
+func (g *Go) generateUnion(object cge.Object) {
+	name := snakeToPascal(object.Name.Lexeme)
+	valueMethod := snakeToCamel(object.Name.Lexeme) + snakeToPascal(object.Discriminator.Lexeme)
+
+	g.imports["encoding/json"] = struct{}{}
+	g.imports["fmt"] = struct{}{}
+
+	g.builder.WriteString("\n")
+	g.generateComments("", object.Comments, object.Deprecated)
+	g.builder.WriteString(fmt.Sprintf("type %s struct {\n", name))
+	g.builder.WriteString(fmt.Sprintf("\tValue %sValue\n", name))
+	g.builder.WriteString("}\n")
+
+	g.builder.WriteString(fmt.Sprintf("\n// %sValue is implemented by all possible types of %s.\n", name, name))
+	g.builder.WriteString(fmt.Sprintf("type %sValue interface {\n", name))
+	g.builder.WriteString(fmt.Sprintf("\t%s() string\n", valueMethod))
+	g.builder.WriteString("}\n")
+
+	for _, member := range object.Properties {
+		g.builder.WriteString(fmt.Sprintf("\nfunc (%s) %s() string {\n", snakeToPascal(member.Name), valueMethod))
+		g.builder.WriteString(fmt.Sprintf("\treturn \"%s\"\n", member.Name))
+		g.builder.WriteString("}\n")
+	}
+
+	g.builder.WriteString(fmt.Sprintf("\nfunc (u %s) MarshalJSON() ([]byte, error) {\n", name))
+	g.builder.WriteString("\tif u.Value == nil {\n")
+	g.builder.WriteString("\t\treturn []byte(\"null\"), nil\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\tdata, err := json.Marshal(u.Value)\n")
+	g.builder.WriteString("\tif err != nil {\n")
+	g.builder.WriteString("\t\treturn nil, err\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\tvar fields map[string]json.RawMessage\n")
+	g.builder.WriteString("\terr = json.Unmarshal(data, &fields)\n")
+	g.builder.WriteString("\tif err != nil {\n")
+	g.builder.WriteString("\t\treturn nil, err\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString(fmt.Sprintf("\tfields[\"%s\"], err = json.Marshal(u.Value.%s())\n", object.Discriminator.Lexeme, valueMethod))
+	g.builder.WriteString("\tif err != nil {\n")
+	g.builder.WriteString("\t\treturn nil, err\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\treturn json.Marshal(fields)\n")
+	g.builder.WriteString("}\n")
+
+	g.builder.WriteString(fmt.Sprintf("\nfunc (u *%s) UnmarshalJSON(data []byte) error {\n", name))
+	g.builder.WriteString("\tif string(data) == \"null\" {\n")
+	g.builder.WriteString("\t\tu.Value = nil\n")
+	g.builder.WriteString("\t\treturn nil\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\tvar discriminator struct {\n")
+	g.builder.WriteString(fmt.Sprintf("\t\tValue string `json:\"%s\"`\n", object.Discriminator.Lexeme))
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\terr := json.Unmarshal(data, &discriminator)\n")
+	g.builder.WriteString("\tif err != nil {\n")
+	g.builder.WriteString("\t\treturn err\n")
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\tswitch discriminator.Value {\n")
+	for _, member := range object.Properties {
+		g.builder.WriteString(fmt.Sprintf("\tcase \"%s\":\n", member.Name))
+		g.builder.WriteString(fmt.Sprintf("\t\tvar value %s\n", snakeToPascal(member.Name)))
+		g.builder.WriteString("\t\terr = json.Unmarshal(data, &value)\n")
+		g.builder.WriteString("\t\tu.Value = value\n")
+	}
+	g.builder.WriteString("\tdefault:\n")
+	g.builder.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"unknown %s %s: %%q\", discriminator.Value)\n", object.Name.Lexeme, object.Discriminator.Lexeme))
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("\treturn err\n")
+	g.builder.WriteString("}\n")
+}
+
+// generateBase embeds the base type of object. The fields of embedded structs are encoded as if they were declared in the outer struct.
+func (g *Go) generateBase(object cge.Object) {
+	if object.Extends != nil {
+		g.builder.WriteString(fmt.Sprintf("\t%s\n", snakeToPascal(object.Extends.Token.Lexeme)))
+	}
+}
+
 func (g *Go) generateProperties(properties []cge.Property) {
 	for _, property := range properties {
-		g.generateComments("\t", property.Comments)
-		g.builder.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", snakeToPascal(property.Name), g.goType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic), property.Name))
+		g.generateComments("\t", property.Comments, property.Deprecated)
+		goType := g.goType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic, property.Type.Key, property.Type.TypeArguments)
+		if property.Optional {
+			if property.Type.Token.Type != cge.LIST && property.Type.Token.Type != cge.MAP && property.Type.Token.Type != cge.BYTES {
+				goType = "*" + goType
+			}
+			g.builder.WriteString(fmt.Sprintf("\t%s %s `json:\"%s,omitempty\"`\n", snakeToPascal(property.Name), goType, property.Name))
+		} else {
+			g.builder.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", snakeToPascal(property.Name), goType, property.Name))
+		}
+	}
+}
+
+func (g *Go) generateDefaults(typeName string, properties []cge.Property) {
+	if !hasDefaults(properties) {
+		return
+	}
+
+	g.builder.WriteString(fmt.Sprintf("\n// New%s returns a new %s with all default values applied.\n", typeName, typeName))
+	g.builder.WriteString(fmt.Sprintf("func New%s() %s {\n", typeName, typeName))
+	g.builder.WriteString(fmt.Sprintf("\treturn %s{\n", typeName))
+	for _, property := range properties {
+		if property.Default == nil {
+			continue
+		}
+		g.builder.WriteString(fmt.Sprintf("\t\t%s: %s,\n", snakeToPascal(property.Name), g.goLiteral(*property.Default, property.Type)))
 	}
+	g.builder.WriteString("\t}\n")
+	g.builder.WriteString("}\n")
 }
 
-func (g *Go) generateComments(indent string, comments []string) {
+// generateValidate generates a Validate method which checks the constraints of all properties.
+// Types without own constraints inherit the Validate method of their embedded base type.
+func (g *Go) generateValidate(typeName string, object cge.Object) {
+	if !hasConstraints(object.Properties) {
+		return
+	}
+
+	g.imports["errors"] = struct{}{}
+
+	// Compiled patterns are stored in unexported package variables named after the type and property.
+	patternPrefix := strings.ToLower(typeName[:1]) + typeName[1:]
+	for _, property := range object.Properties {
+		if pattern := property.Constraint("pattern"); pattern != nil {
+			g.imports["regexp"] = struct{}{}
+			g.builder.WriteString(fmt.Sprintf("\nvar %s%sPattern = regexp.MustCompile(%s)\n", patternPrefix, snakeToPascal(property.Name), strconv.Quote(pattern.Value.Value)))
+		}
+	}
+
+	g.builder.WriteString(fmt.Sprintf("\n// Validate returns an error if a value of %s violates its constraints.\n", typeName))
+	g.builder.WriteString(fmt.Sprintf("func (v %s%s) Validate() error {\n", typeName, goTypeParameters(object.TypeParameters, false)))
+	if baseHasConstraints(g.objects, object) {
+		g.builder.WriteString(fmt.Sprintf("\tif err := v.%s.Validate(); err != nil {\n", snakeToPascal(object.Extends.Token.Lexeme)))
+		g.builder.WriteString("\t\treturn err\n")
+		g.builder.WriteString("\t}\n")
+	}
+	for _, property := range object.Properties {
+		field := "v." + snakeToPascal(property.Name)
+		value := field
+		guard := ""
+		if property.Optional {
+			guard = field + " != nil && "
+			if property.Type.Token.Type != cge.LIST && property.Type.Token.Type != cge.MAP {
+				value = "*" + field
+			}
+		}
+		for _, constraint := range property.Constraints {
+			var condition string
+			switch constraint.Name.Lexeme {
+			case "min":
+				condition = fmt.Sprintf("%s < %s", value, constraint.Value.Value)
+			case "max":
+				condition = fmt.Sprintf("%s > %s", value, constraint.Value.Value)
+			case "min_len":
+				g.imports["unicode/utf8"] = struct{}{}
+				condition = fmt.Sprintf("utf8.RuneCountInString(%s) < %s", value, constraint.Value.Value)
+			case "max_len":
+				g.imports["unicode/utf8"] = struct{}{}
+				condition = fmt.Sprintf("utf8.RuneCountInString(%s) > %s", value, constraint.Value.Value)
+			case "pattern":
+				condition = fmt.Sprintf("!%s%sPattern.MatchString(%s)", patternPrefix, snakeToPascal(property.Name), value)
+			case "min_items":
+				condition = fmt.Sprintf("len(%s) < %s", value, constraint.Value.Value)
+			case "max_items":
+				condition = fmt.Sprintf("len(%s) > %s", value, constraint.Value.Value)
+			}
+			g.builder.WriteString(fmt.Sprintf("\tif %s%s {\n", guard, condition))
+			g.builder.WriteString(fmt.Sprintf("\t\treturn errors.New(%s)\n", strconv.Quote(constraintMessage(property, constraint))))
+			g.builder.WriteString("\t}\n")
+		}
+	}
+	g.builder.WriteString("\treturn nil\n")
+	g.builder.WriteString("}\n")
+}
+
+func (g *Go) generateComments(indent string, comments []string, deprecation *cge.Deprecation) {
 	for _, comment := range comments {
 		g.builder.WriteString(indent + "// " + comment + "\n")
 	}
+	if deprecation != nil {
+		if len(comments) > 0 {
+			g.builder.WriteString(indent + "//\n")
+		}
+		g.builder.WriteString(indent + "// Deprecated: " + deprecationReason(deprecation) + "\n")
+	}
 }
 
-func (g *Go) goType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType) string {
+func (g *Go) goType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType, key *cge.PropertyType, typeArguments []*cge.PropertyType) string {
 	switch tokenType {
 	case cge.STRING:
 		return "string"
@@ -149,16 +522,71 @@ func (g *Go) goType(tokenType cge.TokenType, lexeme string, generic *cge.Propert
 		return "float32"
 	case cge.FLOAT64:
 		return "float64"
+	case cge.UINT32:
+		return "uint32"
+	case cge.UINT64:
+		return "uint64"
+	case cge.BYTES:
+		return "[]byte"
+	case cge.TIMESTAMP:
+		g.imports["time"] = struct{}{}
+		return "time.Time"
+	case cge.DURATION:
+		g.imports["time"] = struct{}{}
+		return "time.Duration"
+	case cge.UUID:
+		return "string"
 	case cge.LIST:
-		return "[]" + g.goType(generic.Token.Type, generic.Token.Lexeme, generic.Generic)
+		return "[]" + g.goType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments)
 	case cge.MAP:
-		return "map[string]" + g.goType(generic.Token.Type, generic.Token.Lexeme, generic.Generic)
+		// encoding/json encodes integer keys as strings
+		keyType := "string"
+		if key != nil {
+			keyType = g.goType(key.Token.Type, key.Token.Lexeme, nil, nil, nil)
+		}
+		return "map[" + keyType + "]" + g.goType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments)
 	case cge.IDENTIFIER:
+		if len(typeArguments) > 0 {
+			arguments := make([]string, len(typeArguments))
+			for i, a := range typeArguments {
+				arguments[i] = g.goType(a.Token.Type, a.Token.Lexeme, a.Generic, a.Key, a.TypeArguments)
+			}
+			return snakeToPascal(lexeme) + "[" + strings.Join(arguments, ", ") + "]"
+		}
+		return snakeToPascal(lexeme)
+	case cge.TYPE_PARAMETER:
 		return snakeToPascal(lexeme)
 	}
 	return "any"
 }
 
+// goTypeParameters returns the type parameter list of a generic type, e.g. '[K, V any]' in a declaration or '[K, V]' in a method receiver.
+func goTypeParameters(typeParameters []cge.Token, declaration bool) string {
+	if len(typeParameters) == 0 {
+		return ""
+	}
+	names := make([]string, len(typeParameters))
+	for i, t := range typeParameters {
+		names[i] = snakeToPascal(t.Lexeme)
+	}
+	if declaration {
+		return "[" + strings.Join(names, ", ") + " any]"
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+func (g *Go) goLiteral(literal cge.Literal, propertyType *cge.PropertyType) string {
+	switch literal.Token.Type {
+	case cge.STRING_LITERAL:
+		return strconv.Quote(literal.Value)
+	case cge.IDENTIFIER:
+		return snakeToPascal(resolveAlias(g.aliases, propertyType).Token.Lexeme) + snakeToPascal(literal.Value)
+	case cge.OPEN_SQUARE, cge.OPEN_CURLY:
+		return g.goType(propertyType.Token.Type, propertyType.Token.Lexeme, propertyType.Generic, propertyType.Key, propertyType.TypeArguments) + "{}"
+	}
+	return literal.Value
+}
+
 func detectPackageName(dir, fallback string) string {
 	path, err := filepath.Abs(dir)
 	if err != nil {
@@ -5,27 +5,43 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/code-game-project/cg-gen-events/cge"
 )
 
 type Java struct {
+	objects          []cge.Object
+	unions           map[string][]string
+	aliases          map[string]cge.Object
 	javaPackage      string
 	importList       bool
 	importDictionary bool
+	// adapters is true if any type requires Adapters.java.
+	adapters bool
 }
 
 func (j *Java) Generate(metadata cge.Metadata, objects []cge.Object, dir string) error {
 	dir = filepath.Join(dir, "definitions")
 	j.javaPackage = j.packageFromDir(dir)
+	j.objects = objects
+	j.unions = unionsByMember(objects)
+	j.aliases = aliasesByName(objects)
+	j.adapters = false
 
 	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
 		return err
 	}
 
+	constants := make([]cge.Object, 0)
 	for _, o := range objects {
+		if o.Type == cge.CONST {
+			constants = append(constants, o)
+			continue
+		}
+
 		filename := snakeToPascal(o.Name.Lexeme) + ".java"
 		switch o.Type {
 		case cge.EVENT:
@@ -50,20 +66,111 @@ func (j *Java) Generate(metadata cge.Metadata, objects []cge.Object, dir string)
 			j.generateEnum(o, file)
 		case cge.TYPE:
 			j.generateType(o, file)
+		case cge.ALIAS:
+			j.generateAlias(o, file)
+		case cge.UNION:
+			j.generateUnion(o, file)
+		}
+		file.Close()
+	}
+
+	file, err := os.Create(filepath.Join(dir, "Constants.java"))
+	if err != nil {
+		return err
+	}
+	j.generateConstants(cge.Fingerprint(metadata, objects), constants, file)
+	file.Close()
+
+	if j.adapters {
+		file, err := os.Create(filepath.Join(dir, "Adapters.java"))
+		if err != nil {
+			return err
 		}
+		j.generateAdapters(file)
 		file.Close()
 	}
 
 	return nil
 }
 
+// generateConstants generates the Constants class with the schema fingerprint and all constants.
+func (j *Java) generateConstants(fingerprint string, constants []cge.Object, writer io.Writer) {
+	fmt.Fprintf(writer, "package %s;\n\n", j.javaPackage)
+	imports := make(map[string]struct{})
+	for _, constant := range constants {
+		if i := javaTypeImport(constant.ValueType.Token.Type); i != "" {
+			imports[i] = struct{}{}
+		}
+	}
+	writeImports(writer, imports)
+	if len(imports) > 0 {
+		fmt.Fprintln(writer)
+	}
+	fmt.Fprintf(writer, "public final class Constants {\n")
+	j.generateComments("    ", fingerprintComments, nil, writer)
+	fmt.Fprintf(writer, "    public static final String SCHEMA_FINGERPRINT = \"%s\";\n", fingerprint)
+	for _, constant := range constants {
+		fmt.Fprintln(writer)
+		j.generateComments("    ", constant.Comments, constant.Deprecated, writer)
+		fmt.Fprintf(writer, "    public static final %s %s = %s;\n", j.javaType(constant.ValueType.Token.Type, constant.ValueType.Token.Lexeme, nil, nil, nil), snakeToUppercase(constant.Name.Lexeme), j.javaLiteral(*constant.Value, constant.ValueType))
+	}
+	fmt.Fprintf(writer, "\n    private Constants() {}\n")
+	fmt.Fprintln(writer, "}")
+}
+
+// generateAdapters generates the Gson adapters for types which are not supported out of the box.
+func (j *Java) generateAdapters(writer io.Writer) {
+	fmt.Fprintf(writer, "package %s;\n\n", j.javaPackage)
+	fmt.Fprintf(writer, "import java.io.IOException;\n")
+	fmt.Fprintf(writer, "import java.time.Duration;\n")
+	fmt.Fprintf(writer, "import java.time.Instant;\n")
+	fmt.Fprintf(writer, "import java.time.OffsetDateTime;\n")
+	fmt.Fprintf(writer, "import java.util.Base64;\n")
+	fmt.Fprintf(writer, "import com.google.gson.TypeAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonReader;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonToken;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonWriter;\n\n")
+
+	fmt.Fprintf(writer, "public final class Adapters {\n")
+	adapters := []struct {
+		name, javaType, write, read string
+	}{
+		{"BytesAdapter", "byte[]", "out.value(Base64.getEncoder().encodeToString(value));", "return Base64.getDecoder().decode(in.nextString());"},
+		{"TimestampAdapter", "Instant", "out.value(value.toString());", "return OffsetDateTime.parse(in.nextString()).toInstant();"},
+		{"DurationAdapter", "Duration", "out.value(value.toNanos());", "return Duration.ofNanos(in.nextLong());"},
+	}
+	for _, a := range adapters {
+		fmt.Fprintf(writer, "    public static class %s extends TypeAdapter<%s> {\n", a.name, a.javaType)
+		fmt.Fprintf(writer, "        @Override\n")
+		fmt.Fprintf(writer, "        public void write(JsonWriter out, %s value) throws IOException {\n", a.javaType)
+		fmt.Fprintf(writer, "            if (value == null) {\n")
+		fmt.Fprintf(writer, "                out.nullValue();\n")
+		fmt.Fprintf(writer, "                return;\n")
+		fmt.Fprintf(writer, "            }\n")
+		fmt.Fprintf(writer, "            %s\n", a.write)
+		fmt.Fprintf(writer, "        }\n\n")
+		fmt.Fprintf(writer, "        @Override\n")
+		fmt.Fprintf(writer, "        public %s read(JsonReader in) throws IOException {\n", a.javaType)
+		fmt.Fprintf(writer, "            if (in.peek() == JsonToken.NULL) {\n")
+		fmt.Fprintf(writer, "                in.nextNull();\n")
+		fmt.Fprintf(writer, "                return null;\n")
+		fmt.Fprintf(writer, "            }\n")
+		fmt.Fprintf(writer, "            %s\n", a.read)
+		fmt.Fprintf(writer, "        }\n")
+		fmt.Fprintf(writer, "    }\n\n")
+	}
+	fmt.Fprintf(writer, "    private Adapters() {}\n")
+	fmt.Fprintln(writer, "}")
+}
+
 func (j *Java) generateConfig(object cge.Object, writer io.Writer) {
 	j.fileHeader(object, writer)
-	j.generateComments("", object.Comments, writer)
+	j.generateComments("", object.Comments, object.Deprecated, writer)
 	fmt.Fprintf(writer, "public class GameConfig {\n")
 	j.generateProperties(object.Properties, writer)
 
 	j.constructors(object, writer)
+	j.generateValidate(object, writer)
 	fmt.Fprintln(writer, "}")
 }
 
@@ -71,24 +178,47 @@ func (j *Java) fileHeader(object cge.Object, writer io.Writer) {
 	fmt.Fprintf(writer, "package %s;\n\n", j.javaPackage)
 	list := false
 	dict := false
-	for _, p := range object.Properties {
-		t := p.Type
-		for t != nil {
-			if t.Token.Type == cge.LIST {
-				list = true
-			}
-			if t.Token.Type == cge.MAP {
-				dict = true
-			}
-			t = t.Generic
+	arrayList := false
+	hashtable := false
+	adapter := false
+	imports := make(map[string]struct{})
+	// inherited properties are part of the constructor parameters
+	for _, p := range append(cge.InheritedProperties(j.objects, object), object.Properties...) {
+		if p.Default != nil {
+			arrayList = arrayList || p.Default.Token.Type == cge.OPEN_SQUARE
+			hashtable = hashtable || p.Default.Token.Type == cge.OPEN_CURLY
+		}
+		// enum values don't have a type
+		if p.Type != nil {
+			adapter = adapter || javaAdapter(p.Type.Token.Type) != ""
 		}
+		if p.Constraint("pattern") != nil {
+			imports["java.util.regex.Pattern"] = struct{}{}
+		}
+		forEachType(p.Type, func(t *cge.PropertyType) {
+			if i := javaTypeImport(t.Token.Type); i != "" {
+				imports[i] = struct{}{}
+			}
+			list = list || t.Token.Type == cge.LIST
+			dict = dict || t.Token.Type == cge.MAP
+		})
 	}
 	if list {
 		fmt.Fprintf(writer, "import java.util.List;\n")
 	}
+	if arrayList {
+		fmt.Fprintf(writer, "import java.util.ArrayList;\n")
+	}
 	if dict {
 		fmt.Fprintf(writer, "import java.util.Dictionary;\n")
 	}
+	if hashtable {
+		fmt.Fprintf(writer, "import java.util.Hashtable;\n")
+	}
+	writeImports(writer, imports)
+	if adapter {
+		fmt.Fprintf(writer, "import com.google.gson.annotations.JsonAdapter;\n")
+	}
 	if len(object.Properties) > 0 {
 		fmt.Fprintf(writer, "import com.google.gson.annotations.SerializedName;\n\n")
 	}
@@ -97,66 +227,337 @@ func (j *Java) fileHeader(object cge.Object, writer io.Writer) {
 func (j *Java) generateCommand(object cge.Object, writer io.Writer) {
 	j.fileHeader(object, writer)
 
-	j.generateComments("", object.Comments, writer)
-	fmt.Fprintf(writer, "public class %sCmd {\n", snakeToPascal(object.Name.Lexeme))
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	fmt.Fprintf(writer, "public class %sCmd%s {\n", snakeToPascal(object.Name.Lexeme), j.extends(object))
 	j.generateProperties(object.Properties, writer)
 
 	j.constructors(object, writer)
+	j.generateValidate(object, writer)
 	fmt.Fprintln(writer, "}")
 }
 
 func (j *Java) generateEvent(object cge.Object, writer io.Writer) {
 	j.fileHeader(object, writer)
 
-	j.generateComments("", object.Comments, writer)
-	fmt.Fprintf(writer, "public class %sEvent {\n", snakeToPascal(object.Name.Lexeme))
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	fmt.Fprintf(writer, "public class %sEvent%s {\n", snakeToPascal(object.Name.Lexeme), j.extends(object))
 	j.generateProperties(object.Properties, writer)
 
 	j.constructors(object, writer)
+	j.generateValidate(object, writer)
 	fmt.Fprintln(writer, "}")
 }
 
 func (j *Java) generateType(object cge.Object, writer io.Writer) {
 	j.fileHeader(object, writer)
 
-	j.generateComments("", object.Comments, writer)
-	fmt.Fprintf(writer, "public class %s {\n", snakeToPascal(object.Name.Lexeme))
+	name := snakeToPascal(object.Name.Lexeme) + typeParameterList(object.TypeParameters)
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	if unions, ok := j.unions[object.Name.Lexeme]; ok {
+		names := make([]string, len(unions))
+		for i, u := range unions {
+			names[i] = snakeToPascal(u)
+		}
+		fmt.Fprintf(writer, "public class %s%s implements %s {\n", name, j.extends(object), strings.Join(names, ", "))
+	} else {
+		fmt.Fprintf(writer, "public class %s%s {\n", name, j.extends(object))
+	}
 	j.generateProperties(object.Properties, writer)
 
 	j.constructors(object, writer)
+	j.generateValidate(object, writer)
+	fmt.Fprintln(writer, "}")
+}
+
+func (j *Java) generateAlias(object cge.Object, writer io.Writer) {
+	name := snakeToPascal(object.Name.Lexeme)
+	valueType := j.javaType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, object.ValueType.Generic, object.ValueType.Key, object.ValueType.TypeArguments)
+	boxedType := j.boxedJavaType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, object.ValueType.Generic, object.ValueType.Key, object.ValueType.TypeArguments)
+
+	fmt.Fprintf(writer, "package %s;\n\n", j.javaPackage)
+	fmt.Fprintf(writer, "import java.io.IOException;\n")
+	list := false
+	dict := false
+	imports := make(map[string]struct{})
+	forEachType(object.ValueType, func(t *cge.PropertyType) {
+		list = list || t.Token.Type == cge.LIST
+		dict = dict || t.Token.Type == cge.MAP
+		if i := javaTypeImport(t.Token.Type); i != "" {
+			imports[i] = struct{}{}
+		}
+	})
+	writeImports(writer, imports)
+	if list {
+		fmt.Fprintf(writer, "import java.util.List;\n")
+	}
+	if dict {
+		fmt.Fprintf(writer, "import java.util.Dictionary;\n")
+	}
+	fmt.Fprintf(writer, "import java.util.Objects;\n")
+	fmt.Fprintf(writer, "import com.google.gson.Gson;\n")
+	fmt.Fprintf(writer, "import com.google.gson.TypeAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.annotations.JsonAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.reflect.TypeToken;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonReader;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonToken;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonWriter;\n\n")
+
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	fmt.Fprintf(writer, "@JsonAdapter(%s.Adapter.class)\n", name)
+	fmt.Fprintf(writer, "public class %s {\n", name)
+	fmt.Fprintf(writer, "    public %s value;\n\n", valueType)
+	fmt.Fprintf(writer, "    public %s(%s value) {\n", name, valueType)
+	fmt.Fprintf(writer, "        this.value = value;\n")
+	fmt.Fprintf(writer, "    }\n\n")
+
+	// Aliases can be used as map keys, which requires value semantics and Gson encodes map keys with toString.
+	fmt.Fprintf(writer, "    @Override\n")
+	fmt.Fprintf(writer, "    public boolean equals(Object o) {\n")
+	fmt.Fprintf(writer, "        return o instanceof %s && Objects.equals(value, ((%s) o).value);\n", name, name)
+	fmt.Fprintf(writer, "    }\n\n")
+	fmt.Fprintf(writer, "    @Override\n")
+	fmt.Fprintf(writer, "    public int hashCode() {\n")
+	fmt.Fprintf(writer, "        return Objects.hashCode(value);\n")
+	fmt.Fprintf(writer, "    }\n\n")
+	fmt.Fprintf(writer, "    @Override\n")
+	fmt.Fprintf(writer, "    public String toString() {\n")
+	fmt.Fprintf(writer, "        return String.valueOf(value);\n")
+	fmt.Fprintf(writer, "    }\n\n")
+
+	fmt.Fprintf(writer, "    public static class Adapter extends TypeAdapter<%s> {\n", name)
+	if adapter := javaAdapter(object.ValueType.Token.Type); adapter != "" {
+		fmt.Fprintf(writer, "        private static final TypeAdapter<%s> adapter = new %s();\n\n", boxedType, adapter)
+	} else {
+		fmt.Fprintf(writer, "        private static final TypeAdapter<%s> adapter = new Gson().getAdapter(new TypeToken<%s>() {});\n\n", boxedType, boxedType)
+	}
+	fmt.Fprintf(writer, "        @Override\n")
+	fmt.Fprintf(writer, "        public void write(JsonWriter out, %s value) throws IOException {\n", name)
+	fmt.Fprintf(writer, "            if (value == null) {\n")
+	fmt.Fprintf(writer, "                out.nullValue();\n")
+	fmt.Fprintf(writer, "                return;\n")
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            adapter.write(out, value.value);\n")
+	fmt.Fprintf(writer, "        }\n\n")
+	fmt.Fprintf(writer, "        @Override\n")
+	fmt.Fprintf(writer, "        public %s read(JsonReader in) throws IOException {\n", name)
+	fmt.Fprintf(writer, "            if (in.peek() == JsonToken.NULL) {\n")
+	fmt.Fprintf(writer, "                in.nextNull();\n")
+	fmt.Fprintf(writer, "                return null;\n")
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            return new %s(adapter.read(in));\n", name)
+	fmt.Fprintf(writer, "        }\n")
+	fmt.Fprintf(writer, "    }\n")
 	fmt.Fprintln(writer, "}")
 }
 
 func (j *Java) generateEnum(object cge.Object, writer io.Writer) {
+	if object.IsIntEnum() {
+		j.generateIntEnum(object, writer)
+		return
+	}
+
 	j.fileHeader(object, writer)
 
-	j.generateComments("", object.Comments, writer)
-	fmt.Fprintf(writer, "public enum %s {\n", snakeToPascal(object.Name.Lexeme))
+	name := snakeToPascal(object.Name.Lexeme)
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	fmt.Fprintf(writer, "public enum %s {\n", name)
+
+	values := object.EnumValues()
+	for i, property := range object.Properties {
+		j.generateComments("    ", property.Comments, property.Deprecated, writer)
+		fmt.Fprintf(writer, "    @SerializedName(%s)\n", quoteString(values[i]))
+		fmt.Fprintf(writer, "    %s(%s)", snakeToUppercase(property.Name), quoteString(values[i]))
+		if i < len(object.Properties)-1 {
+			fmt.Fprintf(writer, ",\n")
+		}
+	}
+	fmt.Fprintf(writer, ";\n\n")
+
+	fmt.Fprintf(writer, "    public final String value;\n\n")
+	fmt.Fprintf(writer, "    %s(String value) {\n", name)
+	fmt.Fprintf(writer, "        this.value = value;\n")
+	fmt.Fprintf(writer, "    }\n\n")
+
+	// Gson encodes map keys with toString instead of the type adapter.
+	fmt.Fprintf(writer, "    @Override\n")
+	fmt.Fprintf(writer, "    public String toString() {\n")
+	fmt.Fprintf(writer, "        return value;\n")
+	fmt.Fprintf(writer, "    }\n")
+	fmt.Fprintln(writer, "}")
+}
 
-	for _, property := range object.Properties {
-		j.generateComments("    ", property.Comments, writer)
-		fmt.Fprintf(writer, "    @SerializedName(\"%s\")\n", property.Name)
-		fmt.Fprintf(writer, "    %s,\n", snakeToUppercase(property.Name))
+func (j *Java) generateIntEnum(object cge.Object, writer io.Writer) {
+	name := snakeToPascal(object.Name.Lexeme)
+	valueType := j.javaType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil)
+	suffix := ""
+	if object.ValueType.Token.Type == cge.INT64 {
+		suffix = "L"
 	}
+
+	fmt.Fprintf(writer, "package %s;\n\n", j.javaPackage)
+	fmt.Fprintf(writer, "import java.io.IOException;\n")
+	fmt.Fprintf(writer, "import com.google.gson.JsonParseException;\n")
+	fmt.Fprintf(writer, "import com.google.gson.TypeAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.annotations.JsonAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonReader;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonToken;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonWriter;\n\n")
+
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	fmt.Fprintf(writer, "@JsonAdapter(%s.Adapter.class)\n", name)
+	fmt.Fprintf(writer, "public enum %s {\n", name)
+
+	values := object.EnumValues()
+	for i, property := range object.Properties {
+		j.generateComments("    ", property.Comments, property.Deprecated, writer)
+		fmt.Fprintf(writer, "    %s(%s%s)", snakeToUppercase(property.Name), values[i], suffix)
+		if i < len(object.Properties)-1 {
+			fmt.Fprintf(writer, ",\n")
+		}
+	}
+	fmt.Fprintf(writer, ";\n\n")
+
+	fmt.Fprintf(writer, "    public final %s value;\n\n", valueType)
+	fmt.Fprintf(writer, "    %s(%s value) {\n", name, valueType)
+	fmt.Fprintf(writer, "        this.value = value;\n")
+	fmt.Fprintf(writer, "    }\n\n")
+
+	// Gson encodes map keys with toString instead of the type adapter.
+	fmt.Fprintf(writer, "    @Override\n")
+	fmt.Fprintf(writer, "    public String toString() {\n")
+	fmt.Fprintf(writer, "        return String.valueOf(value);\n")
+	fmt.Fprintf(writer, "    }\n\n")
+
+	fmt.Fprintf(writer, "    public static class Adapter extends TypeAdapter<%s> {\n", name)
+	fmt.Fprintf(writer, "        @Override\n")
+	fmt.Fprintf(writer, "        public void write(JsonWriter out, %s value) throws IOException {\n", name)
+	fmt.Fprintf(writer, "            if (value == null) {\n")
+	fmt.Fprintf(writer, "                out.nullValue();\n")
+	fmt.Fprintf(writer, "                return;\n")
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            out.value(value.value);\n")
+	fmt.Fprintf(writer, "        }\n\n")
+	fmt.Fprintf(writer, "        @Override\n")
+	fmt.Fprintf(writer, "        public %s read(JsonReader in) throws IOException {\n", name)
+	fmt.Fprintf(writer, "            if (in.peek() == JsonToken.NULL) {\n")
+	fmt.Fprintf(writer, "                in.nextNull();\n")
+	fmt.Fprintf(writer, "                return null;\n")
+	fmt.Fprintf(writer, "            }\n")
+	if object.ValueType.Token.Type == cge.INT64 {
+		fmt.Fprintf(writer, "            long value = in.nextLong();\n")
+	} else {
+		fmt.Fprintf(writer, "            int value = in.nextInt();\n")
+	}
+	fmt.Fprintf(writer, "            for (%s v : values()) {\n", name)
+	fmt.Fprintf(writer, "                if (v.value == value) {\n")
+	fmt.Fprintf(writer, "                    return v;\n")
+	fmt.Fprintf(writer, "                }\n")
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            throw new JsonParseException(\"Unknown %s value: \" + value);\n", object.Name.Lexeme)
+	fmt.Fprintf(writer, "        }\n")
+	fmt.Fprintf(writer, "    }\n")
+	fmt.Fprintln(writer, "}")
+}
+
+func (j *Java) generateUnion(object cge.Object, writer io.Writer) {
+	name := snakeToPascal(object.Name.Lexeme)
+	discriminator := object.Discriminator.Lexeme
+
+	fmt.Fprintf(writer, "package %s;\n\n", j.javaPackage)
+	fmt.Fprintf(writer, "import java.io.IOException;\n")
+	fmt.Fprintf(writer, "import com.google.gson.Gson;\n")
+	fmt.Fprintf(writer, "import com.google.gson.JsonElement;\n")
+	fmt.Fprintf(writer, "import com.google.gson.JsonObject;\n")
+	fmt.Fprintf(writer, "import com.google.gson.JsonParseException;\n")
+	fmt.Fprintf(writer, "import com.google.gson.JsonParser;\n")
+	fmt.Fprintf(writer, "import com.google.gson.TypeAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.annotations.JsonAdapter;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonReader;\n")
+	fmt.Fprintf(writer, "import com.google.gson.stream.JsonWriter;\n\n")
+
+	j.generateComments("", object.Comments, object.Deprecated, writer)
+	fmt.Fprintf(writer, "@JsonAdapter(%s.Adapter.class)\n", name)
+	fmt.Fprintf(writer, "public interface %s {\n", name)
+	fmt.Fprintf(writer, "    class Adapter extends TypeAdapter<%s> {\n", name)
+	fmt.Fprintf(writer, "        private static final Gson gson = new Gson();\n\n")
+
+	fmt.Fprintf(writer, "        @Override\n")
+	fmt.Fprintf(writer, "        public void write(JsonWriter out, %s value) throws IOException {\n", name)
+	fmt.Fprintf(writer, "            if (value == null) {\n")
+	fmt.Fprintf(writer, "                out.nullValue();\n")
+	fmt.Fprintf(writer, "                return;\n")
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            JsonObject object = gson.toJsonTree(value).getAsJsonObject();\n")
+	for i, member := range object.Properties {
+		if i > 0 {
+			fmt.Fprintf(writer, " else ")
+		} else {
+			fmt.Fprintf(writer, "            ")
+		}
+		fmt.Fprintf(writer, "if (value instanceof %s) {\n", snakeToPascal(member.Name))
+		fmt.Fprintf(writer, "                object.addProperty(\"%s\", \"%s\");\n", discriminator, member.Name)
+		fmt.Fprintf(writer, "            }")
+	}
+	if len(object.Properties) > 0 {
+		fmt.Fprintf(writer, "\n")
+	}
+	fmt.Fprintf(writer, "            gson.toJson(object, out);\n")
+	fmt.Fprintf(writer, "        }\n\n")
+
+	fmt.Fprintf(writer, "        @Override\n")
+	fmt.Fprintf(writer, "        public %s read(JsonReader in) throws IOException {\n", name)
+	fmt.Fprintf(writer, "            JsonElement element = JsonParser.parseReader(in);\n")
+	fmt.Fprintf(writer, "            if (element.isJsonNull()) {\n")
+	fmt.Fprintf(writer, "                return null;\n")
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            JsonObject object = element.getAsJsonObject();\n")
+	fmt.Fprintf(writer, "            JsonElement discriminator = object.get(\"%s\");\n", discriminator)
+	fmt.Fprintf(writer, "            if (discriminator == null) {\n")
+	fmt.Fprintf(writer, "                throw new JsonParseException(\"Missing discriminator '%s' in %s.\");\n", discriminator, object.Name.Lexeme)
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "            switch (discriminator.getAsString()) {\n")
+	for _, member := range object.Properties {
+		fmt.Fprintf(writer, "                case \"%s\":\n", member.Name)
+		fmt.Fprintf(writer, "                    return gson.fromJson(object, %s.class);\n", snakeToPascal(member.Name))
+	}
+	fmt.Fprintf(writer, "                default:\n")
+	fmt.Fprintf(writer, "                    throw new JsonParseException(\"Unknown %s %s: \" + discriminator.getAsString());\n", object.Name.Lexeme, discriminator)
+	fmt.Fprintf(writer, "            }\n")
+	fmt.Fprintf(writer, "        }\n")
+	fmt.Fprintf(writer, "    }\n")
 	fmt.Fprintln(writer, "}")
 }
 
 func (j *Java) generateProperties(properties []cge.Property, writer io.Writer) {
 	for _, property := range properties {
-		j.generateComments("    ", property.Comments, writer)
+		j.generateComments("    ", property.Comments, property.Deprecated, writer)
 		fmt.Fprintf(writer, "    @SerializedName(\"%s\")\n", property.Name)
-		fmt.Fprintf(writer, "    public %s %s;\n\n", j.javaType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic), snakeToCamel(property.Name))
+		if adapter := javaAdapter(property.Type.Token.Type); adapter != "" {
+			fmt.Fprintf(writer, "    @JsonAdapter(%s.class)\n", adapter)
+		}
+		if property.Default != nil {
+			fmt.Fprintf(writer, "    public %s %s = %s;\n\n", j.propertyType(property), snakeToCamel(property.Name), j.javaLiteral(*property.Default, property.Type))
+		} else {
+			fmt.Fprintf(writer, "    public %s %s;\n\n", j.propertyType(property), snakeToCamel(property.Name))
+		}
 	}
 }
 
-func (j *Java) generateComments(indent string, comments []string, writer io.Writer) {
-	if len(comments) > 0 {
+// generateComments generates a Javadoc comment and a Deprecated annotation if deprecation is not nil.
+func (j *Java) generateComments(indent string, comments []string, deprecation *cge.Deprecation, writer io.Writer) {
+	if len(comments) > 0 || deprecation != nil {
 		fmt.Fprintf(writer, "%s/**\n", indent)
 		for _, c := range comments {
 			fmt.Fprintf(writer, "%s * %s\n", indent, c)
 		}
+		if deprecation != nil {
+			fmt.Fprintf(writer, "%s * @deprecated %s\n", indent, deprecationReason(deprecation))
+		}
 		fmt.Fprintf(writer, "%s */\n", indent)
 	}
+	if deprecation != nil {
+		fmt.Fprintf(writer, "%s@Deprecated\n", indent)
+	}
 }
 
 func (j *Java) constructors(object cge.Object, writer io.Writer) {
@@ -170,19 +571,28 @@ func (j *Java) constructors(object cge.Object, writer io.Writer) {
 		name = "GameConfig"
 	}
 
-	j.generateComments("    ", object.Comments, writer)
+	j.generateComments("    ", object.Comments, nil, writer)
 	fmt.Fprintf(writer, "    public %s() {}\n\n", name)
 
-	if len(object.Properties) > 0 {
+	inherited := cge.InheritedProperties(j.objects, object)
+	properties := append(inherited, object.Properties...)
+	if len(properties) > 0 {
 		fmt.Fprintf(writer, "    /**\n")
 		for _, c := range object.Comments {
 			fmt.Fprintf(writer, "     * %s\n", c)
 		}
-		for _, p := range object.Properties {
+		for _, p := range properties {
 			fmt.Fprintf(writer, "     * @%s %s\n", snakeToCamel(p.Name), strings.Join(p.Comments, "\n     * "))
 		}
 		fmt.Fprintf(writer, "     */\n")
-		fmt.Fprintf(writer, "    public %s(%s) {\n", name, j.parameterList(object.Properties))
+		fmt.Fprintf(writer, "    public %s(%s) {\n", name, j.parameterList(properties))
+		if len(inherited) > 0 {
+			args := make([]string, len(inherited))
+			for i, p := range inherited {
+				args[i] = snakeToCamel(p.Name)
+			}
+			fmt.Fprintf(writer, "        super(%s);\n", strings.Join(args, ", "))
+		}
 		for _, p := range object.Properties {
 			fmt.Fprintf(writer, "        this.%s = %s;\n", snakeToCamel(p.Name), snakeToCamel(p.Name))
 		}
@@ -190,10 +600,71 @@ func (j *Java) constructors(object cge.Object, writer io.Writer) {
 	}
 }
 
+// generateValidate generates a validate method which throws an IllegalArgumentException if a property violates its constraints.
+func (j *Java) generateValidate(object cge.Object, writer io.Writer) {
+	if !hasConstraints(object.Properties) {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n    /**\n")
+	fmt.Fprintf(writer, "     * @throws IllegalArgumentException if a value violates its constraints.\n")
+	fmt.Fprintf(writer, "     */\n")
+	if baseHasConstraints(j.objects, object) {
+		fmt.Fprintf(writer, "    @Override\n")
+		fmt.Fprintf(writer, "    public void validate() {\n")
+		fmt.Fprintf(writer, "        super.validate();\n")
+	} else {
+		fmt.Fprintf(writer, "    public void validate() {\n")
+	}
+	for _, property := range object.Properties {
+		field := snakeToCamel(property.Name)
+		guard := ""
+		if property.Optional {
+			guard = field + " != null && "
+		}
+		for _, constraint := range property.Constraints {
+			var condition string
+			switch constraint.Name.Lexeme {
+			case "min", "max":
+				operator := "<"
+				if constraint.Name.Lexeme == "max" {
+					operator = ">"
+				}
+				if property.Type.Token.Type == cge.UINT64 {
+					condition = fmt.Sprintf("%s.compareTo(%s) %s 0", field, j.javaLiteral(constraint.Value, property.Type), operator)
+				} else {
+					condition = fmt.Sprintf("%s %s %s", field, operator, j.javaLiteral(constraint.Value, property.Type))
+				}
+			case "min_len":
+				condition = fmt.Sprintf("%s.codePointCount(0, %s.length()) < %s", field, field, constraint.Value.Value)
+			case "max_len":
+				condition = fmt.Sprintf("%s.codePointCount(0, %s.length()) > %s", field, field, constraint.Value.Value)
+			case "pattern":
+				condition = fmt.Sprintf("!Pattern.compile(%s).matcher(%s).find()", quoteString(constraint.Value.Value), field)
+			case "min_items":
+				condition = fmt.Sprintf("%s.size() < %s", field, constraint.Value.Value)
+			case "max_items":
+				condition = fmt.Sprintf("%s.size() > %s", field, constraint.Value.Value)
+			}
+			fmt.Fprintf(writer, "        if (%s%s) {\n", guard, condition)
+			fmt.Fprintf(writer, "            throw new IllegalArgumentException(%s);\n", quoteString(constraintMessage(property, constraint)))
+			fmt.Fprintf(writer, "        }\n")
+		}
+	}
+	fmt.Fprintf(writer, "    }\n")
+}
+
+func (j *Java) extends(object cge.Object) string {
+	if object.Extends == nil {
+		return ""
+	}
+	return " extends " + snakeToPascal(object.Extends.Token.Lexeme)
+}
+
 func (j *Java) parameterList(properties []cge.Property) string {
 	sbuilder := strings.Builder{}
 	for i, p := range properties {
-		sbuilder.WriteString(j.javaType(p.Type.Token.Type, p.Type.Token.Lexeme, p.Type.Generic))
+		sbuilder.WriteString(j.propertyType(p))
 		sbuilder.WriteString(" " + snakeToCamel(p.Name))
 		if i < len(properties)-1 {
 			sbuilder.WriteString(", ")
@@ -202,7 +673,14 @@ func (j *Java) parameterList(properties []cge.Property) string {
 	return sbuilder.String()
 }
 
-func (j *Java) javaType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType) string {
+func (j *Java) propertyType(property cge.Property) string {
+	if property.Optional {
+		return j.boxedJavaType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic, property.Type.Key, property.Type.TypeArguments)
+	}
+	return j.javaType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic, property.Type.Key, property.Type.TypeArguments)
+}
+
+func (j *Java) javaType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType, key *cge.PropertyType, typeArguments []*cge.PropertyType) string {
 	switch tokenType {
 	case cge.STRING:
 		return "String"
@@ -216,16 +694,142 @@ func (j *Java) javaType(tokenType cge.TokenType, lexeme string, generic *cge.Pro
 		return "float"
 	case cge.FLOAT64:
 		return "double"
+	case cge.UINT32:
+		return "long"
+	case cge.UINT64:
+		return "BigInteger"
+	case cge.BYTES:
+		j.adapters = true
+		return "byte[]"
+	case cge.TIMESTAMP:
+		j.adapters = true
+		return "Instant"
+	case cge.DURATION:
+		j.adapters = true
+		return "Duration"
+	case cge.UUID:
+		return "UUID"
 	case cge.LIST:
-		return "List<" + j.javaType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + ">"
+		return "List<" + j.javaElementType(generic) + ">"
 	case cge.MAP:
-		return "Dictionary<String, " + j.javaType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + ">"
+		keyType := "String"
+		if key != nil {
+			keyType = j.boxedJavaType(key.Token.Type, key.Token.Lexeme, nil, nil, nil)
+		}
+		return "Dictionary<" + keyType + ", " + j.javaElementType(generic) + ">"
 	case cge.IDENTIFIER:
+		if len(typeArguments) > 0 {
+			arguments := make([]string, len(typeArguments))
+			for i, a := range typeArguments {
+				arguments[i] = j.javaElementType(a)
+			}
+			return snakeToPascal(lexeme) + "<" + strings.Join(arguments, ", ") + ">"
+		}
+		return snakeToPascal(lexeme)
+	case cge.TYPE_PARAMETER:
 		return snakeToPascal(lexeme)
 	}
 	return "Object"
 }
 
+func (j *Java) boxedJavaType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType, key *cge.PropertyType, typeArguments []*cge.PropertyType) string {
+	switch tokenType {
+	case cge.BOOL:
+		return "Boolean"
+	case cge.INT32:
+		return "Integer"
+	case cge.INT64:
+		return "Long"
+	case cge.FLOAT32:
+		return "Float"
+	case cge.FLOAT64:
+		return "Double"
+	case cge.UINT32:
+		return "Long"
+	}
+	return j.javaType(tokenType, lexeme, generic, key, typeArguments)
+}
+
+// javaElementType returns the type of the elements of a list or map or of a type argument.
+// Types which require an adapter use their JSON wire encoding, because adapters cannot be applied to elements.
+func (j *Java) javaElementType(generic *cge.PropertyType) string {
+	switch generic.Token.Type {
+	case cge.BYTES, cge.TIMESTAMP:
+		return "String"
+	case cge.DURATION:
+		return "Long"
+	}
+	return j.boxedJavaType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments)
+}
+
+// javaAdapter returns the name of the adapter class which is required to encode tokenType or an empty string if no adapter is needed.
+func javaAdapter(tokenType cge.TokenType) string {
+	switch tokenType {
+	case cge.BYTES:
+		return "Adapters.BytesAdapter"
+	case cge.TIMESTAMP:
+		return "Adapters.TimestampAdapter"
+	case cge.DURATION:
+		return "Adapters.DurationAdapter"
+	}
+	return ""
+}
+
+// javaTypeImport returns the import which is required to use tokenType or an empty string if no import is needed.
+func javaTypeImport(tokenType cge.TokenType) string {
+	switch tokenType {
+	case cge.UINT64:
+		return "java.math.BigInteger"
+	case cge.TIMESTAMP:
+		return "java.time.Instant"
+	case cge.DURATION:
+		return "java.time.Duration"
+	case cge.UUID:
+		return "java.util.UUID"
+	}
+	return ""
+}
+
+func writeImports(writer io.Writer, imports map[string]struct{}) {
+	sorted := make([]string, 0, len(imports))
+	for i := range imports {
+		sorted = append(sorted, i)
+	}
+	sort.Strings(sorted)
+	for _, i := range sorted {
+		fmt.Fprintf(writer, "import %s;\n", i)
+	}
+}
+
+func (j *Java) javaLiteral(literal cge.Literal, propertyType *cge.PropertyType) string {
+	if alias, ok := j.aliases[propertyType.Token.Lexeme]; ok && propertyType.Token.Type == cge.IDENTIFIER {
+		return fmt.Sprintf("new %s(%s)", snakeToPascal(alias.Name.Lexeme), j.javaLiteral(literal, alias.ValueType))
+	}
+
+	switch literal.Token.Type {
+	case cge.STRING_LITERAL:
+		return quoteString(literal.Value)
+	case cge.NUMBER:
+		switch propertyType.Token.Type {
+		case cge.INT64, cge.UINT32:
+			return literal.Value + "L"
+		case cge.UINT64:
+			return fmt.Sprintf("new BigInteger(\"%s\")", literal.Value)
+		case cge.FLOAT32:
+			return literal.Value + "f"
+		case cge.FLOAT64:
+			return literal.Value + "d"
+		}
+	case cge.IDENTIFIER:
+		return snakeToPascal(propertyType.Token.Lexeme) + "." + snakeToUppercase(literal.Value)
+	case cge.OPEN_SQUARE:
+		return "new ArrayList<>()"
+	case cge.OPEN_CURLY:
+		return "new Hashtable<>()"
+	}
+	return literal.Value
+}
+
 func (j *Java) packageFromDir(dir string) string {
 	abs, err := filepath.Abs(dir)
 	if err != nil {
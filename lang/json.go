@@ -10,47 +10,111 @@ import (
 )
 
 type jsonObject struct {
-	GameName   string     `json:"game_name"`
-	CGEVersion string     `json:"cge_version"`
-	Comments   []string   `json:"comments,omitempty"`
-	Config     jsonType   `json:"config"`
-	Commands   []jsonType `json:"commands"`
-	Events     []jsonType `json:"events"`
-	Types      []jsonType `json:"types"`
-	Enums      []jsonEnum `json:"enums"`
+	GameName   string `json:"game_name"`
+	CGEVersion string `json:"cge_version"`
+	// Fingerprint is the schema fingerprint returned by cge.Fingerprint.
+	Fingerprint string         `json:"fingerprint"`
+	Comments    []string       `json:"comments,omitempty"`
+	Config      jsonType       `json:"config"`
+	Commands    []jsonType     `json:"commands"`
+	Events      []jsonType     `json:"events"`
+	Types       []jsonType     `json:"types"`
+	Enums       []jsonEnum     `json:"enums"`
+	Unions      []jsonUnion    `json:"unions"`
+	Constants   []jsonConstant `json:"constants"`
+	Aliases     []jsonAlias    `json:"aliases"`
 }
 
 type jsonType struct {
-	Name       string         `json:"name,omitempty"`
-	Comments   []string       `json:"comments,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Comments []string `json:"comments,omitempty"`
+	Extends  string   `json:"extends,omitempty"`
+	// TypeParameters are the names of the type parameters of a generic type.
+	TypeParameters []string `json:"type_parameters,omitempty"`
+	// Responses are the names of the events which can be sent in response to a command.
+	Responses []string `json:"responses,omitempty"`
+	// Scope is the audience of an event.
+	Scope      string         `json:"scope,omitempty"`
 	Properties []jsonProperty `json:"properties"`
+	jsonDeprecation
 }
 
 type jsonProperty struct {
 	Name     string           `json:"name"`
 	Comments []string         `json:"comments,omitempty"`
 	Type     jsonPropertyType `json:"type"`
+	Optional bool             `json:"optional,omitempty"`
+	Default  any              `json:"default,omitempty"`
+	// Constraints maps the names of the constraints of the property to their values.
+	Constraints map[string]any `json:"constraints,omitempty"`
+	jsonDeprecation
+}
+
+// jsonDeprecation is embedded into every element which can be deprecated.
+type jsonDeprecation struct {
+	Deprecated        bool   `json:"deprecated,omitempty"`
+	DeprecationReason string `json:"deprecation_reason,omitempty"`
 }
 
 type jsonPropertyType struct {
 	Name    string            `json:"name"`
 	Generic *jsonPropertyType `json:"generic,omitempty"`
+	// Key is the key type of a map. It is omitted for maps with string keys which don't specify a key type.
+	Key *jsonPropertyType `json:"key,omitempty"`
+	// TypeArguments are the type arguments of an instantiation of a generic type.
+	TypeArguments []*jsonPropertyType `json:"type_arguments,omitempty"`
+	// TypeParameter is true if Name is the name of a type parameter of the enclosing generic type.
+	TypeParameter bool `json:"type_parameter,omitempty"`
 }
 
 type jsonEnum struct {
 	Name     string          `json:"name"`
 	Comments []string        `json:"comments,omitempty"`
+	Type     string          `json:"type,omitempty"`
 	Values   []jsonEnumValue `json:"values"`
+	jsonDeprecation
 }
 
 type jsonEnumValue struct {
 	Name     string   `json:"name"`
 	Comments []string `json:"comments,omitempty"`
+	Value    any      `json:"value"`
+	jsonDeprecation
+}
+
+type jsonUnion struct {
+	Name          string            `json:"name"`
+	Comments      []string          `json:"comments,omitempty"`
+	Discriminator string            `json:"discriminator"`
+	Members       []jsonUnionMember `json:"members"`
+	jsonDeprecation
+}
+
+type jsonUnionMember struct {
+	Name     string   `json:"name"`
+	Comments []string `json:"comments,omitempty"`
+}
+
+type jsonConstant struct {
+	Name     string           `json:"name"`
+	Comments []string         `json:"comments,omitempty"`
+	Type     jsonPropertyType `json:"type"`
+	Value    any              `json:"value"`
+	jsonDeprecation
+}
+
+type jsonAlias struct {
+	Name     string           `json:"name"`
+	Comments []string         `json:"comments,omitempty"`
+	Type     jsonPropertyType `json:"type"`
+	jsonDeprecation
 }
 
 type JSON struct {
 	builder strings.Builder
 	json    jsonObject
+	enums   map[string]cge.Object
+	aliases map[string]cge.Object
 }
 
 func (j *JSON) Generate(metadata cge.Metadata, objects []cge.Object, dir string) error {
@@ -62,14 +126,26 @@ func (j *JSON) Generate(metadata cge.Metadata, objects []cge.Object, dir string)
 
 	j.builder = strings.Builder{}
 
+	j.aliases = aliasesByName(objects)
+	j.enums = make(map[string]cge.Object)
+	for _, object := range objects {
+		if object.Type == cge.ENUM {
+			j.enums[object.Name.Lexeme] = object
+		}
+	}
+
 	j.json = jsonObject{
-		GameName:   metadata.Name,
-		CGEVersion: metadata.CGEVersion,
-		Comments:   metadata.Comments,
-		Commands:   make([]jsonType, 0),
-		Events:     make([]jsonType, 0),
-		Types:      make([]jsonType, 0),
-		Enums:      make([]jsonEnum, 0),
+		GameName:    metadata.Name,
+		CGEVersion:  metadata.CGEVersion,
+		Fingerprint: cge.Fingerprint(metadata, objects),
+		Comments:    metadata.Comments,
+		Commands:    make([]jsonType, 0),
+		Events:      make([]jsonType, 0),
+		Types:       make([]jsonType, 0),
+		Enums:       make([]jsonEnum, 0),
+		Unions:      make([]jsonUnion, 0),
+		Constants:   make([]jsonConstant, 0),
+		Aliases:     make([]jsonAlias, 0),
 	}
 
 	for _, object := range objects {
@@ -81,6 +157,12 @@ func (j *JSON) Generate(metadata cge.Metadata, objects []cge.Object, dir string)
 			j.generateEvent(object)
 		} else if object.Type == cge.TYPE {
 			j.generateType(object)
+		} else if object.Type == cge.UNION {
+			j.generateUnion(object)
+		} else if object.Type == cge.CONST {
+			j.generateConstant(object)
+		} else if object.Type == cge.ALIAS {
+			j.generateAlias(object)
 		} else {
 			j.generateEnum(object)
 		}
@@ -91,40 +173,114 @@ func (j *JSON) Generate(metadata cge.Metadata, objects []cge.Object, dir string)
 
 func (j *JSON) generateConfig(object cge.Object) {
 	j.json.Config = jsonType{
-		Comments:   object.Comments,
-		Properties: j.generateProperties(object.Properties),
+		Comments:        object.Comments,
+		Properties:      j.generateProperties(object.Properties),
+		jsonDeprecation: deprecation(object.Deprecated),
 	}
 }
 
 func (j *JSON) generateCommand(object cge.Object) {
 	j.json.Commands = append(j.json.Commands, jsonType{
-		Name:       object.Name.Lexeme,
-		Comments:   object.Comments,
-		Properties: j.generateProperties(object.Properties),
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Extends:         j.extends(object),
+		Responses:       j.responses(object),
+		Properties:      j.generateProperties(object.Properties),
+		jsonDeprecation: deprecation(object.Deprecated),
 	})
 }
 
+func (j *JSON) responses(object cge.Object) []string {
+	names := make([]string, len(object.Responses))
+	for i, r := range object.Responses {
+		names[i] = r.Lexeme
+	}
+	return names
+}
+
 func (j *JSON) generateEvent(object cge.Object) {
 	j.json.Events = append(j.json.Events, jsonType{
-		Name:       object.Name.Lexeme,
-		Comments:   object.Comments,
-		Properties: j.generateProperties(object.Properties),
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Extends:         j.extends(object),
+		Scope:           object.Scope.Lexeme,
+		Properties:      j.generateProperties(object.Properties),
+		jsonDeprecation: deprecation(object.Deprecated),
 	})
 }
 
 func (j *JSON) generateType(object cge.Object) {
 	j.json.Types = append(j.json.Types, jsonType{
-		Name:       object.Name.Lexeme,
-		Comments:   object.Comments,
-		Properties: j.generateProperties(object.Properties),
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Extends:         j.extends(object),
+		TypeParameters:  j.typeParameters(object),
+		Properties:      j.generateProperties(object.Properties),
+		jsonDeprecation: deprecation(object.Deprecated),
 	})
 }
 
+func (j *JSON) typeParameters(object cge.Object) []string {
+	names := make([]string, len(object.TypeParameters))
+	for i, t := range object.TypeParameters {
+		names[i] = t.Lexeme
+	}
+	return names
+}
+
+func (j *JSON) extends(object cge.Object) string {
+	if object.Extends == nil {
+		return ""
+	}
+	return object.Extends.Token.Lexeme
+}
+
 func (j *JSON) generateEnum(object cge.Object) {
-	j.json.Enums = append(j.json.Enums, jsonEnum{
-		Name:     object.Name.Lexeme,
-		Comments: object.Comments,
-		Values:   j.generateEnumValues(object.Properties),
+	enum := jsonEnum{
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Values:          j.generateEnumValues(object),
+		jsonDeprecation: deprecation(object.Deprecated),
+	}
+	if object.ValueType != nil {
+		enum.Type = strings.ToLower(string(object.ValueType.Token.Type))
+	}
+	j.json.Enums = append(j.json.Enums, enum)
+}
+
+func (j *JSON) generateUnion(object cge.Object) {
+	members := make([]jsonUnionMember, len(object.Properties))
+	for i, m := range object.Properties {
+		members[i] = jsonUnionMember{
+			Name:     m.Name,
+			Comments: m.Comments,
+		}
+	}
+	j.json.Unions = append(j.json.Unions, jsonUnion{
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Discriminator:   object.Discriminator.Lexeme,
+		Members:         members,
+		jsonDeprecation: deprecation(object.Deprecated),
+	})
+}
+
+func (j *JSON) generateConstant(object cge.Object) {
+	j.json.Constants = append(j.json.Constants, jsonConstant{
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Type:            *j.generatePropertyType(object.ValueType),
+		Value:           j.generateLiteral(*object.Value, object.ValueType),
+		jsonDeprecation: deprecation(object.Deprecated),
+	})
+}
+
+func (j *JSON) generateAlias(object cge.Object) {
+	j.json.Aliases = append(j.json.Aliases, jsonAlias{
+		Name:            object.Name.Lexeme,
+		Comments:        object.Comments,
+		Type:            *j.generatePropertyType(object.ValueType),
+		jsonDeprecation: deprecation(object.Deprecated),
 	})
 }
 
@@ -132,9 +288,20 @@ func (j *JSON) generateProperties(properties []cge.Property) []jsonProperty {
 	props := make([]jsonProperty, len(properties))
 	for i, p := range properties {
 		props[i] = jsonProperty{
-			Name:     p.Name,
-			Comments: p.Comments,
-			Type:     *j.generatePropertyType(p.Type),
+			Name:            p.Name,
+			Comments:        p.Comments,
+			Type:            *j.generatePropertyType(p.Type),
+			Optional:        p.Optional,
+			jsonDeprecation: deprecation(p.Deprecated),
+		}
+		if p.Default != nil {
+			props[i].Default = j.generateLiteral(*p.Default, p.Type)
+		}
+		if len(p.Constraints) > 0 {
+			props[i].Constraints = make(map[string]any, len(p.Constraints))
+			for _, c := range p.Constraints {
+				props[i].Constraints[c.Name.Lexeme] = j.generateLiteral(c.Value, p.Type)
+			}
 		}
 	}
 	return props
@@ -149,20 +316,76 @@ func (j *JSON) generatePropertyType(propertyType *cge.PropertyType) *jsonPropert
 		t.Name = propertyType.Token.Lexeme
 	}
 
+	if propertyType.Token.Type == cge.TYPE_PARAMETER {
+		t.Name = propertyType.Token.Lexeme
+		t.TypeParameter = true
+	}
+
 	if propertyType.Generic != nil {
 		t.Generic = j.generatePropertyType(propertyType.Generic)
 	}
 
+	if propertyType.Key != nil {
+		t.Key = j.generatePropertyType(propertyType.Key)
+	}
+
+	for _, a := range propertyType.TypeArguments {
+		t.TypeArguments = append(t.TypeArguments, j.generatePropertyType(a))
+	}
+
 	return t
 }
 
-func (j *JSON) generateEnumValues(properties []cge.Property) []jsonEnumValue {
-	values := make([]jsonEnumValue, len(properties))
-	for i, p := range properties {
+func (j *JSON) generateLiteral(literal cge.Literal, propertyType *cge.PropertyType) any {
+	switch literal.Token.Type {
+	case cge.IDENTIFIER:
+		enum := j.enums[resolveAlias(j.aliases, propertyType).Token.Lexeme]
+		for i, v := range enum.Properties {
+			if v.Name == literal.Value {
+				return j.enumValue(enum, enum.EnumValues()[i])
+			}
+		}
+	case cge.NUMBER:
+		return json.Number(literal.Value)
+	case cge.TRUE:
+		return true
+	case cge.FALSE:
+		return false
+	case cge.OPEN_SQUARE:
+		return []any{}
+	case cge.OPEN_CURLY:
+		return map[string]any{}
+	}
+	return literal.Value
+}
+
+func (j *JSON) generateEnumValues(object cge.Object) []jsonEnumValue {
+	enumValues := object.EnumValues()
+	values := make([]jsonEnumValue, len(object.Properties))
+	for i, p := range object.Properties {
 		values[i] = jsonEnumValue{
-			Name:     p.Name,
-			Comments: p.Comments,
+			Name:            p.Name,
+			Comments:        p.Comments,
+			Value:           j.enumValue(object, enumValues[i]),
+			jsonDeprecation: deprecation(p.Deprecated),
 		}
 	}
 	return values
 }
+
+func (j *JSON) enumValue(enum cge.Object, value string) any {
+	if enum.IsIntEnum() {
+		return json.Number(value)
+	}
+	return value
+}
+
+func deprecation(deprecation *cge.Deprecation) jsonDeprecation {
+	if deprecation == nil {
+		return jsonDeprecation{}
+	}
+	return jsonDeprecation{
+		Deprecated:        true,
+		DeprecationReason: deprecation.Reason,
+	}
+}
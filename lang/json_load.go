@@ -0,0 +1,456 @@
+package lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+// constraintOrder is the order of the constraints of a property loaded from JSON, which stores them unordered.
+var constraintOrder = []string{"min", "max", "min_len", "max_len", "pattern", "min_items", "max_items"}
+
+// jsonLoader converts the structure written by the JSON generator back into a syntax tree.
+type jsonLoader struct {
+	enums   map[string]jsonEnum
+	aliases map[string]jsonPropertyType
+}
+
+// LoadJSON reads an events.json file written by the JSON generator and returns the syntax tree it was generated from.
+// The result can be passed to other generators or written back to a CGE file with cge.Print.
+//
+// The tokens of the syntax tree don't have positions. Information which is not part of the JSON output is lost:
+// imports, attributes other than '@deprecated', whether a type was declared inline and the order of the declarations of different kinds.
+// Enum values only get an explicit value if it differs from the implicit one.
+// A config without properties, comments and deprecation is treated as the implicit config object.
+func LoadJSON(source io.Reader) (cge.Metadata, []cge.Object, error) {
+	decoder := json.NewDecoder(source)
+	decoder.UseNumber()
+	var data jsonObject
+	err := decoder.Decode(&data)
+	if err != nil {
+		return cge.Metadata{}, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	l := &jsonLoader{
+		enums:   make(map[string]jsonEnum, len(data.Enums)),
+		aliases: make(map[string]jsonPropertyType, len(data.Aliases)),
+	}
+	for _, e := range data.Enums {
+		l.enums[e.Name] = e
+	}
+	for _, a := range data.Aliases {
+		l.aliases[a.Name] = a.Type
+	}
+
+	metadata := cge.Metadata{
+		Name:          data.GameName,
+		CGEVersion:    data.CGEVersion,
+		Comments:      loadComments(data.Comments),
+		NameToken:     cge.Token{Type: cge.IDENTIFIER, Lexeme: data.GameName},
+		VersionToken:  cge.Token{Type: cge.VERSION_NUMBER, Lexeme: data.CGEVersion},
+		CommentTokens: commentTokens(data.Comments),
+	}
+
+	objects := make([]cge.Object, 0)
+	implicitConfig := len(data.Config.Properties) == 0 && len(data.Config.Comments) == 0 && !data.Config.Deprecated
+	if !implicitConfig {
+		config, err := l.loadType(cge.CONFIG, data.Config)
+		if err != nil {
+			return cge.Metadata{}, nil, err
+		}
+		objects = append(objects, config)
+	}
+	for _, types := range []struct {
+		tokenType cge.TokenType
+		types     []jsonType
+	}{{cge.COMMAND, data.Commands}, {cge.EVENT, data.Events}, {cge.TYPE, data.Types}} {
+		for _, t := range types.types {
+			object, err := l.loadType(types.tokenType, t)
+			if err != nil {
+				return cge.Metadata{}, nil, err
+			}
+			objects = append(objects, object)
+		}
+	}
+	for _, e := range data.Enums {
+		object, err := l.loadEnum(e)
+		if err != nil {
+			return cge.Metadata{}, nil, err
+		}
+		objects = append(objects, object)
+	}
+	for _, u := range data.Unions {
+		objects = append(objects, l.loadUnion(u))
+	}
+	for _, c := range data.Constants {
+		object, err := l.loadConstant(c)
+		if err != nil {
+			return cge.Metadata{}, nil, err
+		}
+		objects = append(objects, object)
+	}
+	for _, a := range data.Aliases {
+		valueType, err := l.loadPropertyType(a.Type)
+		if err != nil {
+			return cge.Metadata{}, nil, fmt.Errorf("alias '%s': %w", a.Name, err)
+		}
+		objects = append(objects, l.object(cge.ALIAS, a.Name, a.Comments, a.jsonDeprecation, func(o *cge.Object) {
+			o.ValueType = valueType
+		}))
+	}
+	if implicitConfig {
+		objects = append(objects, cge.Object{
+			Type:     cge.CONFIG,
+			Implicit: true,
+		})
+	}
+
+	return metadata, objects, nil
+}
+
+// object creates an object with the common fields of all declarations and calls init to set the remaining fields.
+func (l *jsonLoader) object(tokenType cge.TokenType, name string, comments []string, deprecation jsonDeprecation, init func(o *cge.Object)) cge.Object {
+	object := cge.Object{
+		Comments:      loadComments(comments),
+		CommentTokens: commentTokens(comments),
+		Type:          tokenType,
+		Name:          cge.Token{Type: cge.IDENTIFIER, Lexeme: name},
+	}
+	object.Attributes, object.Deprecated = loadDeprecation(deprecation)
+	if init != nil {
+		init(&object)
+	}
+	return object
+}
+
+func (l *jsonLoader) loadType(tokenType cge.TokenType, t jsonType) (cge.Object, error) {
+	properties, err := l.loadProperties(t.Properties)
+	if err != nil {
+		if tokenType == cge.CONFIG {
+			return cge.Object{}, fmt.Errorf("config: %w", err)
+		}
+		return cge.Object{}, fmt.Errorf("%s '%s': %w", strings.ToLower(string(tokenType)), t.Name, err)
+	}
+
+	return l.object(tokenType, t.Name, t.Comments, t.jsonDeprecation, func(o *cge.Object) {
+		if tokenType == cge.CONFIG {
+			o.Name = cge.Token{Type: cge.CONFIG, Lexeme: "config"}
+		}
+		o.Properties = properties
+		if t.Extends != "" {
+			o.Extends = &cge.PropertyType{
+				Token: cge.Token{Type: cge.IDENTIFIER, Lexeme: t.Extends},
+			}
+		}
+		o.TypeParameters = identifierTokens(t.TypeParameters)
+		o.Responses = identifierTokens(t.Responses)
+		if t.Scope != "" {
+			o.Scope = cge.Token{Type: cge.IDENTIFIER, Lexeme: t.Scope}
+		}
+	}), nil
+}
+
+func (l *jsonLoader) loadProperties(properties []jsonProperty) ([]cge.Property, error) {
+	result := make([]cge.Property, 0, len(properties))
+	for _, p := range properties {
+		propertyType, err := l.loadPropertyType(p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("property '%s': %w", p.Name, err)
+		}
+		property := cge.Property{
+			Comments:      loadComments(p.Comments),
+			CommentTokens: commentTokens(p.Comments),
+			Name:          p.Name,
+			NameToken:     cge.Token{Type: cge.IDENTIFIER, Lexeme: p.Name},
+			Type:          propertyType,
+			Optional:      p.Optional,
+		}
+		property.Attributes, property.Deprecated = loadDeprecation(p.jsonDeprecation)
+
+		if p.Default != nil {
+			property.Default, err = l.loadLiteral(p.Default, p.Type)
+			if err != nil {
+				return nil, fmt.Errorf("default value of property '%s': %w", p.Name, err)
+			}
+		}
+
+		names := make([]string, 0, len(p.Constraints))
+		for name := range p.Constraints {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return constraintIndex(names[i]) < constraintIndex(names[j])
+		})
+		for _, name := range names {
+			value, err := l.loadLiteral(p.Constraints[name], p.Type)
+			if err != nil {
+				return nil, fmt.Errorf("constraint '%s' of property '%s': %w", name, p.Name, err)
+			}
+			property.Constraints = append(property.Constraints, cge.Constraint{
+				Name:  cge.Token{Type: cge.IDENTIFIER, Lexeme: name},
+				Value: *value,
+			})
+		}
+
+		result = append(result, property)
+	}
+	return result, nil
+}
+
+func (l *jsonLoader) loadEnum(e jsonEnum) (cge.Object, error) {
+	var valueType *cge.PropertyType
+	if e.Type != "" {
+		var err error
+		valueType, err = l.loadPropertyType(jsonPropertyType{Name: e.Type})
+		if err != nil {
+			return cge.Object{}, fmt.Errorf("enum '%s': %w", e.Name, err)
+		}
+	}
+	intEnum := e.Type == "int32" || e.Type == "int64"
+
+	properties := make([]cge.Property, 0, len(e.Values))
+	next := int64(0)
+	for _, v := range e.Values {
+		property := cge.Property{
+			Comments:      loadComments(v.Comments),
+			CommentTokens: commentTokens(v.Comments),
+			Name:          v.Name,
+			NameToken:     cge.Token{Type: cge.IDENTIFIER, Lexeme: v.Name},
+		}
+		property.Attributes, property.Deprecated = loadDeprecation(v.jsonDeprecation)
+
+		if intEnum {
+			number, ok := v.Value.(json.Number)
+			if !ok {
+				return cge.Object{}, fmt.Errorf("enum '%s': expected number as value of '%s'", e.Name, v.Name)
+			}
+			value, err := strconv.ParseInt(string(number), 10, 64)
+			if err != nil {
+				return cge.Object{}, fmt.Errorf("enum '%s': invalid value of '%s': %w", e.Name, v.Name, err)
+			}
+			if value != next {
+				property.Value = numberLiteral(string(number))
+			}
+			next = value + 1
+		} else {
+			value, ok := v.Value.(string)
+			if !ok {
+				return cge.Object{}, fmt.Errorf("enum '%s': expected string as value of '%s'", e.Name, v.Name)
+			}
+			if value != v.Name {
+				property.Value = stringLiteral(value)
+			}
+		}
+		properties = append(properties, property)
+	}
+
+	return l.object(cge.ENUM, e.Name, e.Comments, e.jsonDeprecation, func(o *cge.Object) {
+		o.ValueType = valueType
+		o.Properties = properties
+	}), nil
+}
+
+func (l *jsonLoader) loadUnion(u jsonUnion) cge.Object {
+	members := make([]cge.Property, len(u.Members))
+	for i, m := range u.Members {
+		name := cge.Token{Type: cge.IDENTIFIER, Lexeme: m.Name}
+		members[i] = cge.Property{
+			Comments:      loadComments(m.Comments),
+			CommentTokens: commentTokens(m.Comments),
+			Name:          m.Name,
+			NameToken:     name,
+			Type:          &cge.PropertyType{Token: name},
+		}
+	}
+	return l.object(cge.UNION, u.Name, u.Comments, u.jsonDeprecation, func(o *cge.Object) {
+		o.Discriminator = cge.Token{Type: cge.IDENTIFIER, Lexeme: u.Discriminator}
+		o.Properties = members
+	})
+}
+
+func (l *jsonLoader) loadConstant(c jsonConstant) (cge.Object, error) {
+	valueType, err := l.loadPropertyType(c.Type)
+	if err != nil {
+		return cge.Object{}, fmt.Errorf("constant '%s': %w", c.Name, err)
+	}
+	value, err := l.loadLiteral(c.Value, c.Type)
+	if err != nil {
+		return cge.Object{}, fmt.Errorf("constant '%s': %w", c.Name, err)
+	}
+	return l.object(cge.CONST, c.Name, c.Comments, c.jsonDeprecation, func(o *cge.Object) {
+		o.ValueType = valueType
+		o.Value = value
+	}), nil
+}
+
+func (l *jsonLoader) loadPropertyType(t jsonPropertyType) (*cge.PropertyType, error) {
+	result := &cge.PropertyType{}
+
+	tokenType, ok := primitiveTypes[t.Name]
+	switch {
+	case t.TypeParameter:
+		result.Token = cge.Token{Type: cge.TYPE_PARAMETER, Lexeme: t.Name}
+	case ok:
+		result.Token = cge.Token{Type: tokenType, Lexeme: t.Name}
+	case t.Name == "":
+		return nil, fmt.Errorf("missing type name")
+	default:
+		result.Token = cge.Token{Type: cge.IDENTIFIER, Lexeme: t.Name}
+	}
+
+	var err error
+	if t.Generic != nil {
+		result.Generic, err = l.loadPropertyType(*t.Generic)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.Key != nil {
+		result.Key, err = l.loadPropertyType(*t.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range t.TypeArguments {
+		argument, err := l.loadPropertyType(*a)
+		if err != nil {
+			return nil, err
+		}
+		result.TypeArguments = append(result.TypeArguments, argument)
+	}
+
+	if (result.Token.Type == cge.MAP || result.Token.Type == cge.LIST) && result.Generic == nil {
+		return nil, fmt.Errorf("missing generic type of '%s'", t.Name)
+	}
+	return result, nil
+}
+
+// loadLiteral converts a default value, constraint value or constant value of the specified type.
+// Values of enums are converted back to the names of the enum values.
+func (l *jsonLoader) loadLiteral(value any, propertyType jsonPropertyType) (*cge.Literal, error) {
+	for !propertyType.TypeParameter {
+		aliased, ok := l.aliases[propertyType.Name]
+		if !ok {
+			break
+		}
+		propertyType = aliased
+	}
+
+	if enum, ok := l.enums[propertyType.Name]; ok && !propertyType.TypeParameter {
+		for _, v := range enum.Values {
+			if fmt.Sprint(v.Value) == fmt.Sprint(value) {
+				return &cge.Literal{
+					Token: cge.Token{Type: cge.IDENTIFIER, Lexeme: v.Name},
+					Value: v.Name,
+				}, nil
+			}
+		}
+		return nil, fmt.Errorf("'%v' is not a value of enum '%s'", value, enum.Name)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return stringLiteral(v), nil
+	case json.Number:
+		return numberLiteral(string(v)), nil
+	case bool:
+		if v {
+			return &cge.Literal{Token: cge.Token{Type: cge.TRUE, Lexeme: "true"}, Value: "true"}, nil
+		}
+		return &cge.Literal{Token: cge.Token{Type: cge.FALSE, Lexeme: "false"}, Value: "false"}, nil
+	case []any:
+		if len(v) > 0 {
+			return nil, fmt.Errorf("only empty lists are supported as literals")
+		}
+		return &cge.Literal{Token: cge.Token{Type: cge.OPEN_SQUARE, Lexeme: "["}, Value: "[]"}, nil
+	case map[string]any:
+		if len(v) > 0 {
+			return nil, fmt.Errorf("only empty maps are supported as literals")
+		}
+		return &cge.Literal{Token: cge.Token{Type: cge.OPEN_CURLY, Lexeme: "{"}, Value: "{}"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported literal '%v'", value)
+	}
+}
+
+// primitiveTypes maps the type names used by the JSON generator to the token types of all types which are not declared by the user.
+var primitiveTypes = map[string]cge.TokenType{}
+
+func init() {
+	for _, t := range []cge.TokenType{cge.STRING, cge.BOOL, cge.INT32, cge.INT64, cge.FLOAT32, cge.FLOAT64, cge.UINT32, cge.UINT64, cge.BYTES, cge.TIMESTAMP, cge.DURATION, cge.UUID, cge.MAP, cge.LIST} {
+		primitiveTypes[strings.ToLower(string(t))] = t
+	}
+}
+
+func stringLiteral(value string) *cge.Literal {
+	return &cge.Literal{
+		Token: cge.Token{Type: cge.STRING_LITERAL, Lexeme: strconv.Quote(value)},
+		Value: value,
+	}
+}
+
+func numberLiteral(value string) *cge.Literal {
+	return &cge.Literal{
+		Token: cge.Token{Type: cge.NUMBER, Lexeme: value},
+		Value: value,
+	}
+}
+
+// loadDeprecation returns the '@deprecated' attribute and the deprecation described by deprecation.
+func loadDeprecation(deprecation jsonDeprecation) ([]cge.Attribute, *cge.Deprecation) {
+	if !deprecation.Deprecated {
+		return nil, nil
+	}
+	name := cge.Token{Type: cge.IDENTIFIER, Lexeme: "deprecated"}
+	attribute := cge.Attribute{
+		Name: name,
+	}
+	if deprecation.DeprecationReason != "" {
+		attribute.Arguments = []cge.Literal{*stringLiteral(deprecation.DeprecationReason)}
+	}
+	return []cge.Attribute{attribute}, &cge.Deprecation{
+		Token:  name,
+		Reason: deprecation.DeprecationReason,
+	}
+}
+
+// loadComments splits multi-line comments into lines, because every line is a separate comment in a CGE file.
+func loadComments(comments []string) []string {
+	var result []string
+	for _, c := range comments {
+		for _, line := range strings.Split(c, "\n") {
+			result = append(result, strings.TrimSpace(line))
+		}
+	}
+	return result
+}
+
+func commentTokens(comments []string) []cge.Token {
+	var tokens []cge.Token
+	for _, c := range loadComments(comments) {
+		tokens = append(tokens, cge.Token{Type: cge.COMMENT, Lexeme: c})
+	}
+	return tokens
+}
+
+func identifierTokens(names []string) []cge.Token {
+	var tokens []cge.Token
+	for _, n := range names {
+		tokens = append(tokens, cge.Token{Type: cge.IDENTIFIER, Lexeme: n})
+	}
+	return tokens
+}
+
+func constraintIndex(name string) int {
+	for i, n := range constraintOrder {
+		if n == name {
+			return i
+		}
+	}
+	return len(constraintOrder)
+}
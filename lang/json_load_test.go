@@ -0,0 +1,363 @@
+package lang
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
+
+// roundTripSources cover every construct which is represented in events.json.
+var roundTripSources = map[string]string{
+	"game": `// A game.
+//
+// With a second paragraph.
+name game
+version 0.9
+
+// The config.
+config {
+	// The maximum number of players.
+	max_players: int [min=1, max=8] = 4,
+	title: string [min_len=1, max_len=20, pattern="^[a-z]+\"$"] = "a\tb",
+	color: color = green,
+	tags: list<string> [max_items=5] = [],
+	scores: map<int> = {},
+	hard: bool = false,
+	offset: int64 = -10
+}
+
+// Join a team.
+command join_team -> team_joined, join_failed {
+	team: string,
+	@deprecated("Use 'color'.")
+	dir: enum direction { up, down } = down,
+	size: size = large
+}
+
+@deprecated
+command leave {}
+
+event(private) team_joined extends base {
+	team: string
+}
+
+event join_failed {
+	reason: string
+}
+
+type base {
+	id: player_id
+}
+
+// A generic type.
+type change<t, u> {
+	old: t,
+	new: list<t>,
+	lookup: map<int32, u>,
+	nested: list<change<u, t>>
+}
+
+type board {
+	cells: map<color, list<bool>>,
+	changes: change<string, board>,
+	ids: map<player_id, uint64>,
+	data: bytes,
+	at: timestamp,
+	took: duration,
+	uid: uuid,
+	f: float32,
+	g: float64,
+	h: uint32
+}
+
+enum color {
+	red,
+	// The color of grass.
+	green = "GREEN",
+	@deprecated("Not used anymore.")
+	blue
+}
+
+enum size: int64 {
+	small,
+	medium = 5,
+	large,
+	huge = 2
+}
+
+enum dir_code: int32 {
+	up = 0,
+	down = 1
+}
+
+enum mode {
+	fast = "fast",
+	slow
+}
+
+// A shape.
+union shape(kind) {
+	// A square.
+	base,
+	board
+}
+
+const max_speed: float64 = 1.5
+
+@deprecated("Gone.")
+const default_size: size = medium
+
+// A player ID.
+type player_id = string
+`,
+	"minimal": `name minimal
+version 0.4
+`,
+}
+
+// canonicalSource is already in the order and layout of the JSON generator, so the round trip must not change its syntax tree at all.
+const canonicalSource = `// Comment.
+name canonical
+version 0.9
+
+config {
+	speed: float32 = 1.5
+}
+
+command move -> moved {
+	dir: direction = up
+}
+
+event(broadcast) moved {
+	// The new position.
+	pos?: map<int64, list<labeled<string>>> [min_items=1]
+}
+
+type position {
+	x: int32 [min=0, max=10]
+}
+
+type labeled<t> {
+	label: t,
+	pos: position
+}
+
+@deprecated("Use 'heading'.")
+enum direction {
+	up,
+	down = "DOWN"
+}
+
+union any_position(kind) {
+	position
+}
+
+const max: int32 = 10
+
+type id = uuid
+`
+
+func TestJSONRoundTrip(t *testing.T) {
+	for name, source := range roundTripSources {
+		t.Run(name, func(t *testing.T) {
+			original := parse(t, source)
+			originalJSON := generateJSON(t, original.Metadata, original.Objects)
+
+			metadata, objects, err := LoadJSON(bytes.NewReader(originalJSON))
+			if err != nil {
+				t.Fatalf("failed to load JSON: %s", err)
+			}
+
+			printed := cge.Print(metadata, objects)
+			reparsed := parse(t, string(printed))
+			assertEqualAST(t, cge.File{Metadata: metadata, Objects: objects}, *reparsed, string(printed))
+
+			if json := generateJSON(t, reparsed.Metadata, reparsed.Objects); !bytes.Equal(originalJSON, json) {
+				t.Errorf("JSON of the printed source differs from the original:\n%s\n%s", originalJSON, json)
+			}
+			if a, b := cge.Fingerprint(original.Metadata, original.Objects), cge.Fingerprint(metadata, objects); a != b {
+				t.Errorf("fingerprint changed from %s to %s", a, b)
+			}
+
+			formatted, errs := cge.Format(bytes.NewReader(printed), "printed.cge")
+			if len(errs) > 0 {
+				t.Fatalf("failed to format printed source: %v", errs)
+			}
+			if !bytes.Equal(formatted, printed) {
+				t.Errorf("printed source is not formatted:\n%s", printed)
+			}
+
+			assertEqualAST(t, normalizeRoundTrip(*original), normalizeRoundTrip(*reparsed), string(printed))
+		})
+	}
+}
+
+func TestJSONRoundTripCanonical(t *testing.T) {
+	original := parse(t, canonicalSource)
+
+	metadata, objects, err := LoadJSON(bytes.NewReader(generateJSON(t, original.Metadata, original.Objects)))
+	if err != nil {
+		t.Fatalf("failed to load JSON: %s", err)
+	}
+
+	printed := cge.Print(metadata, objects)
+	if string(printed) != canonicalSource {
+		t.Errorf("expected printed source to equal the original:\n%s", printed)
+	}
+	assertEqualAST(t, *original, *parse(t, string(printed)), string(printed))
+}
+
+func TestLoadJSONErrors(t *testing.T) {
+	tests := map[string]string{
+		"invalid JSON":     `{`,
+		"unknown enum":     `{"config":{},"commands":[{"name":"a","properties":[{"name":"b","type":{"name":"c"},"default":"x"}]}],"enums":[{"name":"c","values":[{"name":"d","value":"d"}]}]}`,
+		"missing generic":  `{"config":{"properties":[{"name":"a","type":{"name":"list"}}]}}`,
+		"missing type":     `{"config":{"properties":[{"name":"a","type":{}}]}}`,
+		"non-empty list":   `{"config":{"properties":[{"name":"a","type":{"name":"list","generic":{"name":"int32"}},"default":[1]}]}}`,
+		"int enum value":   `{"config":{},"enums":[{"name":"a","type":"int32","values":[{"name":"b","value":"b"}]}]}`,
+		"string enum type": `{"config":{},"enums":[{"name":"a","values":[{"name":"b","value":1}]}]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := LoadJSON(strings.NewReader(data))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func parse(t *testing.T, source string) *cge.File {
+	t.Helper()
+	file, errs := cge.ParseAST(strings.NewReader(source), "test.cge", "dev")
+	if len(errs) > 0 {
+		t.Fatalf("failed to parse:\n%s\n%v", source, errs)
+	}
+	return file
+}
+
+func generateJSON(t *testing.T, metadata cge.Metadata, objects []cge.Object) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	err := (&JSON{}).Generate(metadata, objects, dir)
+	if err != nil {
+		t.Fatalf("failed to generate JSON: %s", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "events.json"))
+	if err != nil {
+		t.Fatalf("failed to read events.json: %s", err)
+	}
+	return data
+}
+
+// assertEqualAST compares the metadata and objects of two files, ignoring all source positions.
+func assertEqualAST(t *testing.T, expected, actual cge.File, source string) {
+	t.Helper()
+	a := []any{expected.Metadata, expected.Objects}
+	b := []any{actual.Metadata, actual.Objects}
+	normalize(reflect.ValueOf(&a).Elem())
+	normalize(reflect.ValueOf(&b).Elem())
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("syntax trees differ for:\n%s\nexpected: %+v\nactual:   %+v", source, a, b)
+	}
+}
+
+var (
+	rangeType = reflect.TypeOf(cge.Range{})
+	tokenType = reflect.TypeOf(cge.Token{})
+)
+
+// normalize removes all positions from v and replaces empty slices with nil.
+func normalize(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// The value inside of an interface is not addressable.
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		normalize(elem)
+		v.Set(elem)
+	case reflect.Pointer:
+		if !v.IsNil() {
+			normalize(v.Elem())
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			normalize(v.Index(i))
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case rangeType:
+			v.Set(reflect.Zero(rangeType))
+		case tokenType:
+			token := v.Addr().Interface().(*cge.Token)
+			token.Line, token.Column, token.File = 0, 0, ""
+		default:
+			for i := 0; i < v.NumField(); i++ {
+				normalize(v.Field(i))
+			}
+		}
+	}
+}
+
+// normalizeRoundTrip removes the differences between a parsed file and the same file after a round trip through events.json
+// which are expected, because events.json doesn't represent them:
+//   - the order of declarations, because events.json groups them by kind and declares inline enums separately
+//   - whether an empty config was declared or added implicitly
+//   - explicit enum values which are equal to their implicit value
+//   - the spelling of built-in types, e.g. 'int' instead of 'int32'
+//
+// It modifies the property types of file.
+func normalizeRoundTrip(file cge.File) cge.File {
+	objects := make([]cge.Object, 0, len(file.Objects))
+	for _, o := range file.Objects {
+		if o.Type == cge.CONFIG && len(o.Properties) == 0 && len(o.Comments) == 0 && o.Deprecated == nil {
+			continue
+		}
+		if o.Type == cge.ENUM {
+			o.Properties = append([]cge.Property(nil), o.Properties...)
+			values := o.EnumValues()
+			for i := range o.Properties {
+				implicit := o.Properties[i].Name
+				if o.IsIntEnum() {
+					implicit = "0"
+					if i > 0 {
+						previous, _ := strconv.ParseInt(values[i-1], 10, 64)
+						implicit = strconv.FormatInt(previous+1, 10)
+					}
+				}
+				if values[i] == implicit {
+					o.Properties[i].Value = nil
+				}
+			}
+		}
+		cge.Inspect(&o, func(node cge.Node) bool {
+			if t, ok := node.(*cge.PropertyType); ok && t.Token.Type != cge.IDENTIFIER && t.Token.Type != cge.TYPE_PARAMETER {
+				t.Token.Lexeme = strings.ToLower(string(t.Token.Type))
+			}
+			return true
+		})
+		objects = append(objects, o)
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		if objects[i].Type != objects[j].Type {
+			return objects[i].Type < objects[j].Type
+		}
+		return objects[i].Name.Lexeme < objects[j].Name.Lexeme
+	})
+	file.Objects = objects
+	return file
+}
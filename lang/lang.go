@@ -1,7 +1,141 @@
 package lang
 
-import "github.com/code-game-project/cg-gen-events/cge"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/code-game-project/cg-gen-events/cge"
+)
 
 type Generator interface {
 	Generate(metadata cge.Metadata, objects []cge.Object, dir string) error
 }
+
+// fingerprintComments documents the generated constant which contains the schema fingerprint.
+var fingerprintComments = []string{
+	"A hash of the CGE schema these definitions were generated from.",
+	"Clients and servers with the same fingerprint use compatible messages.",
+}
+
+func hasDefaults(properties []cge.Property) bool {
+	for _, p := range properties {
+		if p.Default != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteString returns text as a double-quoted string literal that is valid in JSON, TypeScript, C# and Java.
+func quoteString(text string) string {
+	quoted, _ := json.Marshal(text)
+	return string(quoted)
+}
+
+// unionsByMember maps the name of every type which is a member of a union to the names of all of its unions.
+func unionsByMember(objects []cge.Object) map[string][]string {
+	unions := make(map[string][]string)
+	for _, o := range objects {
+		if o.Type != cge.UNION {
+			continue
+		}
+		for _, m := range o.Properties {
+			unions[m.Name] = append(unions[m.Name], o.Name.Lexeme)
+		}
+	}
+	return unions
+}
+
+// aliasesByName maps the name of every alias to its declaration.
+func aliasesByName(objects []cge.Object) map[string]cge.Object {
+	aliases := make(map[string]cge.Object)
+	for _, o := range objects {
+		if o.Type == cge.ALIAS {
+			aliases[o.Name.Lexeme] = o
+		}
+	}
+	return aliases
+}
+
+// resolveAlias follows propertyType through all aliases and returns the first type which is not an alias.
+func resolveAlias(aliases map[string]cge.Object, propertyType *cge.PropertyType) *cge.PropertyType {
+	for propertyType.Token.Type == cge.IDENTIFIER {
+		alias, ok := aliases[propertyType.Token.Lexeme]
+		if !ok {
+			break
+		}
+		propertyType = alias.ValueType
+	}
+	return propertyType
+}
+
+// forEachType calls f for propertyType and all types it is composed of (generics, map keys and type arguments).
+func forEachType(propertyType *cge.PropertyType, f func(t *cge.PropertyType)) {
+	if propertyType == nil {
+		return
+	}
+	f(propertyType)
+	forEachType(propertyType.Key, f)
+	forEachType(propertyType.Generic, f)
+	for _, a := range propertyType.TypeArguments {
+		forEachType(a, f)
+	}
+}
+
+// typeParameterList returns the type parameters of a generic type in angle brackets as used by TypeScript, C# and Java, e.g. '<K, V>'.
+// It returns an empty string for types without type parameters.
+func typeParameterList(typeParameters []cge.Token) string {
+	if len(typeParameters) == 0 {
+		return ""
+	}
+	names := make([]string, len(typeParameters))
+	for i, t := range typeParameters {
+		names[i] = snakeToPascal(t.Lexeme)
+	}
+	return "<" + strings.Join(names, ", ") + ">"
+}
+
+// deprecationReason returns the reason of deprecation or a generic explanation if no reason was given.
+func deprecationReason(deprecation *cge.Deprecation) string {
+	if deprecation.Reason == "" {
+		return "It will be removed in a future version."
+	}
+	return deprecation.Reason
+}
+
+func hasConstraints(properties []cge.Property) bool {
+	for _, p := range properties {
+		if len(p.Constraints) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// baseHasConstraints returns true if any property inherited by object has a constraint.
+// The generated validation of object has to call the validation of its base type in that case.
+func baseHasConstraints(objects []cge.Object, object cge.Object) bool {
+	return object.Extends != nil && hasConstraints(cge.InheritedProperties(objects, object))
+}
+
+// constraintMessage returns the error message of a value of property which violates constraint.
+func constraintMessage(property cge.Property, constraint cge.Constraint) string {
+	switch constraint.Name.Lexeme {
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", property.Name, constraint.Value.Value)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", property.Name, constraint.Value.Value)
+	case "min_len":
+		return fmt.Sprintf("%s must have at least %s characters", property.Name, constraint.Value.Value)
+	case "max_len":
+		return fmt.Sprintf("%s must have at most %s characters", property.Name, constraint.Value.Value)
+	case "pattern":
+		return fmt.Sprintf("%s must match the pattern %s", property.Name, constraint.Value.Value)
+	case "min_items":
+		return fmt.Sprintf("%s must have at least %s items", property.Name, constraint.Value.Value)
+	case "max_items":
+		return fmt.Sprintf("%s must have at most %s items", property.Name, constraint.Value.Value)
+	}
+	return fmt.Sprintf("%s is invalid", property.Name)
+}
@@ -10,11 +10,14 @@ import (
 )
 
 type MarkdownDocs struct {
-	configTextBuilder  strings.Builder
-	commandTextBuilder strings.Builder
-	eventTextBuilder   strings.Builder
-	typeTextBuilder    strings.Builder
-	enumTextBuilder    strings.Builder
+	objects             []cge.Object
+	configTextBuilder   strings.Builder
+	commandTextBuilder  strings.Builder
+	eventTextBuilder    strings.Builder
+	typeTextBuilder     strings.Builder
+	enumTextBuilder     strings.Builder
+	unionTextBuilder    strings.Builder
+	constantTextBuilder strings.Builder
 }
 
 func (m *MarkdownDocs) Generate(metadata cge.Metadata, objects []cge.Object, dir string) error {
@@ -23,6 +26,7 @@ func (m *MarkdownDocs) Generate(metadata cge.Metadata, objects []cge.Object, dir
 		return err
 	}
 
+	m.objects = objects
 	for _, object := range objects {
 		if object.Type == cge.CONFIG {
 			m.generateConfig(object)
@@ -32,6 +36,12 @@ func (m *MarkdownDocs) Generate(metadata cge.Metadata, objects []cge.Object, dir
 			m.generateEvent(object)
 		} else if object.Type == cge.TYPE {
 			m.generateType(object)
+		} else if object.Type == cge.ALIAS {
+			m.generateAlias(object)
+		} else if object.Type == cge.UNION {
+			m.generateUnion(object)
+		} else if object.Type == cge.CONST {
+			m.generateConstant(object)
 		} else {
 			m.generateEnum(object)
 		}
@@ -65,6 +75,14 @@ func (m *MarkdownDocs) Generate(metadata cge.Metadata, objects []cge.Object, dir
 
 	file.WriteString(m.enumTextBuilder.String())
 
+	file.WriteString("\n")
+
+	file.WriteString(m.unionTextBuilder.String())
+
+	file.WriteString("\n")
+
+	file.WriteString(m.constantTextBuilder.String())
+
 	file.Close()
 
 	return nil
@@ -72,6 +90,7 @@ func (m *MarkdownDocs) Generate(metadata cge.Metadata, objects []cge.Object, dir
 
 func (m *MarkdownDocs) generateConfig(object cge.Object) {
 	m.configTextBuilder.WriteString("## Game Config\n\n")
+	m.generateDeprecation(&m.configTextBuilder, object.Deprecated)
 
 	for _, comment := range object.Comments {
 		m.configTextBuilder.WriteString(comment + "\n")
@@ -90,6 +109,7 @@ func (m *MarkdownDocs) generateCommand(object cge.Object) {
 	}
 	m.commandTextBuilder.WriteString("\n")
 	m.commandTextBuilder.WriteString(fmt.Sprintf("### %s\n\n", object.Name.Lexeme))
+	m.generateDeprecation(&m.commandTextBuilder, object.Deprecated)
 
 	for _, comment := range object.Comments {
 		m.commandTextBuilder.WriteString(comment + "\n")
@@ -99,7 +119,22 @@ func (m *MarkdownDocs) generateCommand(object cge.Object) {
 		m.commandTextBuilder.WriteString("\n")
 	}
 
-	m.generateProperties(&m.commandTextBuilder, object.Properties)
+	if len(object.Responses) > 0 {
+		responses := make([]string, len(object.Responses))
+		for i, r := range object.Responses {
+			responses[i] = fmt.Sprintf("[%s](#%s)", r.Lexeme, r.Lexeme)
+		}
+		m.commandTextBuilder.WriteString(fmt.Sprintf("Responses: %s\n\n", strings.Join(responses, ", ")))
+	}
+
+	m.generateBase(&m.commandTextBuilder, object)
+	m.generateProperties(&m.commandTextBuilder, append(cge.InheritedProperties(m.objects, object), object.Properties...))
+}
+
+var scopeDescriptions = map[string]string{
+	"private":   "only sent to the affected player",
+	"spectator": "only sent to spectators",
+	"broadcast": "sent to all players",
 }
 
 func (m *MarkdownDocs) generateEvent(object cge.Object) {
@@ -108,6 +143,7 @@ func (m *MarkdownDocs) generateEvent(object cge.Object) {
 	}
 	m.eventTextBuilder.WriteString("\n")
 	m.eventTextBuilder.WriteString(fmt.Sprintf("### %s\n\n", object.Name.Lexeme))
+	m.generateDeprecation(&m.eventTextBuilder, object.Deprecated)
 
 	for _, comment := range object.Comments {
 		m.eventTextBuilder.WriteString(comment + "\n")
@@ -117,7 +153,12 @@ func (m *MarkdownDocs) generateEvent(object cge.Object) {
 		m.eventTextBuilder.WriteString("\n")
 	}
 
-	m.generateProperties(&m.eventTextBuilder, object.Properties)
+	if description, ok := scopeDescriptions[object.Scope.Lexeme]; ok {
+		m.eventTextBuilder.WriteString(fmt.Sprintf("Scope: %s (%s)\n\n", object.Scope.Lexeme, description))
+	}
+
+	m.generateBase(&m.eventTextBuilder, object)
+	m.generateProperties(&m.eventTextBuilder, append(cge.InheritedProperties(m.objects, object), object.Properties...))
 }
 
 func (m *MarkdownDocs) generateType(object cge.Object) {
@@ -126,6 +167,35 @@ func (m *MarkdownDocs) generateType(object cge.Object) {
 	}
 	m.typeTextBuilder.WriteString("\n")
 	m.typeTextBuilder.WriteString(fmt.Sprintf("### %s\n\n", object.Name.Lexeme))
+	m.generateDeprecation(&m.typeTextBuilder, object.Deprecated)
+
+	for _, comment := range object.Comments {
+		m.typeTextBuilder.WriteString(comment + "\n")
+	}
+
+	if len(object.Comments) > 0 {
+		m.typeTextBuilder.WriteString("\n")
+	}
+
+	if len(object.TypeParameters) > 0 {
+		names := make([]string, len(object.TypeParameters))
+		for i, t := range object.TypeParameters {
+			names[i] = t.Lexeme
+		}
+		m.typeTextBuilder.WriteString(fmt.Sprintf("Type parameters: %s\n\n", strings.Join(names, ", ")))
+	}
+
+	m.generateBase(&m.typeTextBuilder, object)
+	m.generateProperties(&m.typeTextBuilder, append(cge.InheritedProperties(m.objects, object), object.Properties...))
+}
+
+func (m *MarkdownDocs) generateAlias(object cge.Object) {
+	if m.typeTextBuilder.Len() == 0 {
+		m.typeTextBuilder.WriteString("## Types\n")
+	}
+	m.typeTextBuilder.WriteString("\n")
+	m.typeTextBuilder.WriteString(fmt.Sprintf("### %s\n\n", object.Name.Lexeme))
+	m.generateDeprecation(&m.typeTextBuilder, object.Deprecated)
 
 	for _, comment := range object.Comments {
 		m.typeTextBuilder.WriteString(comment + "\n")
@@ -135,7 +205,7 @@ func (m *MarkdownDocs) generateType(object cge.Object) {
 		m.typeTextBuilder.WriteString("\n")
 	}
 
-	m.generateProperties(&m.typeTextBuilder, object.Properties)
+	m.typeTextBuilder.WriteString(fmt.Sprintf("Alias of: %s\n", m.mdType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, object.ValueType.Generic, object.ValueType.Key, object.ValueType.TypeArguments)))
 }
 
 func (m *MarkdownDocs) generateEnum(object cge.Object) {
@@ -144,6 +214,7 @@ func (m *MarkdownDocs) generateEnum(object cge.Object) {
 	}
 	m.enumTextBuilder.WriteString("\n")
 	m.enumTextBuilder.WriteString(fmt.Sprintf("### %s\n\n", object.Name.Lexeme))
+	m.generateDeprecation(&m.enumTextBuilder, object.Deprecated)
 
 	for _, comment := range object.Comments {
 		m.enumTextBuilder.WriteString(comment + "\n")
@@ -158,12 +229,106 @@ func (m *MarkdownDocs) generateEnum(object cge.Object) {
 		return
 	}
 
+	explicitValues := object.IsIntEnum()
+	for _, property := range object.Properties {
+		explicitValues = explicitValues || property.Value != nil
+	}
+
+	if object.IsIntEnum() {
+		m.enumTextBuilder.WriteString(fmt.Sprintf("Type: %s\n\n", m.mdType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil)))
+	}
+
 	m.enumTextBuilder.WriteString("Possible values:\n")
-	m.enumTextBuilder.WriteString("| Value | Description |\n")
-	m.enumTextBuilder.WriteString("| ----- | ----------- |\n")
+	if explicitValues {
+		m.enumTextBuilder.WriteString("| Name | Value | Description |\n")
+		m.enumTextBuilder.WriteString("| ---- | ----- | ----------- |\n")
+	} else {
+		m.enumTextBuilder.WriteString("| Value | Description |\n")
+		m.enumTextBuilder.WriteString("| ----- | ----------- |\n")
+	}
 
-	for _, property := range object.Properties {
-		m.enumTextBuilder.WriteString(fmt.Sprintf("| %s | %s |\n", property.Name, strings.Join(property.Comments, " ")))
+	values := object.EnumValues()
+	for i, property := range object.Properties {
+		if explicitValues {
+			value := values[i]
+			if !object.IsIntEnum() {
+				value = quoteString(value)
+			}
+			m.enumTextBuilder.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", mdName(property.Name, property.Deprecated), value, mdDescription(property.Comments, property.Deprecated)))
+		} else {
+			m.enumTextBuilder.WriteString(fmt.Sprintf("| %s | %s |\n", mdName(property.Name, property.Deprecated), mdDescription(property.Comments, property.Deprecated)))
+		}
+	}
+}
+
+func (m *MarkdownDocs) generateUnion(object cge.Object) {
+	if m.unionTextBuilder.Len() == 0 {
+		m.unionTextBuilder.WriteString("## Unions\n")
+	}
+	m.unionTextBuilder.WriteString("\n")
+	m.unionTextBuilder.WriteString(fmt.Sprintf("### %s\n\n", object.Name.Lexeme))
+	m.generateDeprecation(&m.unionTextBuilder, object.Deprecated)
+
+	for _, comment := range object.Comments {
+		m.unionTextBuilder.WriteString(comment + "\n")
+	}
+
+	if len(object.Comments) > 0 {
+		m.unionTextBuilder.WriteString("\n")
+	}
+
+	m.unionTextBuilder.WriteString(fmt.Sprintf("Discriminator: `%s`\n\n", object.Discriminator.Lexeme))
+
+	if len(object.Properties) == 0 {
+		m.unionTextBuilder.WriteString("Possible types: none\n")
+		return
+	}
+
+	m.unionTextBuilder.WriteString("Possible types:\n")
+	m.unionTextBuilder.WriteString("| Type | Description |\n")
+	m.unionTextBuilder.WriteString("| ---- | ----------- |\n")
+
+	for _, member := range object.Properties {
+		m.unionTextBuilder.WriteString(fmt.Sprintf("| [%s](#%s) | %s |\n", member.Name, member.Name, strings.Join(member.Comments, " ")))
+	}
+}
+
+func (m *MarkdownDocs) generateConstant(object cge.Object) {
+	if m.constantTextBuilder.Len() == 0 {
+		m.constantTextBuilder.WriteString("## Constants\n\n")
+		m.constantTextBuilder.WriteString("| Name | Type | Value | Description |\n")
+		m.constantTextBuilder.WriteString("| ---- | ---- | ----- | ----------- |\n")
+	}
+
+	m.constantTextBuilder.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s |\n", mdName(object.Name.Lexeme, object.Deprecated), m.mdType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil), object.Value.String(), mdDescription(object.Comments, object.Deprecated)))
+}
+
+func (m *MarkdownDocs) generateDeprecation(builder *strings.Builder, deprecation *cge.Deprecation) {
+	if deprecation != nil {
+		builder.WriteString(fmt.Sprintf("**Deprecated:** %s\n\n", deprecationReason(deprecation)))
+	}
+}
+
+// mdName returns name with a strike-through if it is deprecated.
+func mdName(name string, deprecation *cge.Deprecation) string {
+	if deprecation != nil {
+		return "~~" + name + "~~"
+	}
+	return name
+}
+
+// mdDescription returns the text of the description column of a table row.
+func mdDescription(comments []string, deprecation *cge.Deprecation) string {
+	description := strings.Join(comments, " ")
+	if deprecation != nil {
+		description = strings.TrimSpace(fmt.Sprintf("**Deprecated:** %s %s", deprecationReason(deprecation), description))
+	}
+	return description
+}
+
+func (m *MarkdownDocs) generateBase(builder *strings.Builder, object cge.Object) {
+	if object.Extends != nil {
+		builder.WriteString(fmt.Sprintf("Extends: [%s](#%s)\n\n", object.Extends.Token.Lexeme, object.Extends.Token.Lexeme))
 	}
 }
 
@@ -173,16 +338,84 @@ func (m *MarkdownDocs) generateProperties(builder *strings.Builder, properties [
 		return
 	}
 
+	defaults := hasDefaults(properties)
+	constraints := hasConstraints(properties)
+
 	builder.WriteString("Properties:\n")
-	builder.WriteString("| Name | Type | Description |\n")
-	builder.WriteString("| ---- | ---- | ----------- |\n")
+	builder.WriteString("| Name | Type |")
+	if defaults {
+		builder.WriteString(" Default |")
+	}
+	if constraints {
+		builder.WriteString(" Constraints |")
+	}
+	builder.WriteString(" Description |\n")
+	builder.WriteString("| ---- | ---- |")
+	if defaults {
+		builder.WriteString(" ------- |")
+	}
+	if constraints {
+		builder.WriteString(" ----------- |")
+	}
+	builder.WriteString(" ----------- |\n")
 
 	for _, property := range properties {
-		builder.WriteString(fmt.Sprintf("| %s | %s | %s |\n", property.Name, m.mdType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic), strings.Join(property.Comments, " ")))
+		mdType := m.mdType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic, property.Type.Key, property.Type.TypeArguments)
+		if property.Optional {
+			mdType += " (optional)"
+		}
+		builder.WriteString(fmt.Sprintf("| %s | %s |", mdName(property.Name, property.Deprecated), mdType))
+		if defaults {
+			var defaultValue string
+			if property.Default != nil {
+				defaultValue = "`" + property.Default.String() + "`"
+			}
+			builder.WriteString(fmt.Sprintf(" %s |", defaultValue))
+		}
+		if constraints {
+			var constraintText string
+			if len(property.Constraints) > 0 {
+				texts := make([]string, len(property.Constraints))
+				for i, c := range property.Constraints {
+					texts[i] = c.String()
+				}
+				// '|' would end the table cell even inside of code spans.
+				constraintText = "`" + strings.ReplaceAll(strings.Join(texts, ", "), "|", "\\|") + "`"
+			}
+			builder.WriteString(fmt.Sprintf(" %s |", constraintText))
+		}
+		description := strings.TrimSpace(mdDescription(property.Comments, property.Deprecated) + " " + m.typeArgumentDescription(property.Type))
+		builder.WriteString(fmt.Sprintf(" %s |\n", description))
 	}
 }
 
-func (m *MarkdownDocs) mdType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType) string {
+// typeArgumentDescription explains which type arguments are used for the type parameters of all generic types in propertyType,
+// e.g. 't of [change](#change) is int32.'
+func (m *MarkdownDocs) typeArgumentDescription(propertyType *cge.PropertyType) string {
+	generics := make(map[string]cge.Object)
+	for _, o := range m.objects {
+		if len(o.TypeParameters) > 0 {
+			generics[o.Name.Lexeme] = o
+		}
+	}
+
+	arguments := make([]string, 0)
+	forEachType(propertyType, func(t *cge.PropertyType) {
+		generic, ok := generics[t.Token.Lexeme]
+		if !ok || t.Token.Type != cge.IDENTIFIER || len(generic.TypeParameters) != len(t.TypeArguments) {
+			return
+		}
+		for i, a := range t.TypeArguments {
+			arguments = append(arguments, fmt.Sprintf("%s of [%s](#%s) is %s", generic.TypeParameters[i].Lexeme, t.Token.Lexeme, t.Token.Lexeme, m.mdType(a.Token.Type, a.Token.Lexeme, a.Generic, a.Key, a.TypeArguments)))
+		}
+	})
+	if len(arguments) == 0 {
+		return ""
+	}
+	return strings.Join(arguments, ", ") + "."
+}
+
+func (m *MarkdownDocs) mdType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType, key *cge.PropertyType, typeArguments []*cge.PropertyType) string {
 	switch tokenType {
 	case cge.STRING:
 		return "string"
@@ -196,12 +429,36 @@ func (m *MarkdownDocs) mdType(tokenType cge.TokenType, lexeme string, generic *c
 		return "float32"
 	case cge.FLOAT64:
 		return "float64"
+	case cge.UINT32:
+		return "uint32"
+	case cge.UINT64:
+		return "uint64"
+	case cge.BYTES:
+		return "bytes"
+	case cge.TIMESTAMP:
+		return "timestamp"
+	case cge.DURATION:
+		return "duration"
+	case cge.UUID:
+		return "uuid"
 	case cge.LIST:
-		return "list\\<" + m.mdType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + "\\>"
+		return "list\\<" + m.mdType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments) + "\\>"
 	case cge.MAP:
-		return "map\\<" + m.mdType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + "\\>"
+		if key != nil {
+			return "map\\<" + m.mdType(key.Token.Type, key.Token.Lexeme, nil, nil, nil) + ", " + m.mdType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments) + "\\>"
+		}
+		return "map\\<" + m.mdType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments) + "\\>"
 	case cge.IDENTIFIER:
+		if len(typeArguments) > 0 {
+			arguments := make([]string, len(typeArguments))
+			for i, a := range typeArguments {
+				arguments[i] = m.mdType(a.Token.Type, a.Token.Lexeme, a.Generic, a.Key, a.TypeArguments)
+			}
+			return fmt.Sprintf("[%s](#%s)\\<%s\\>", lexeme, lexeme, strings.Join(arguments, ", "))
+		}
 		return fmt.Sprintf("[%s](#%s)", lexeme, lexeme)
+	case cge.TYPE_PARAMETER:
+		return lexeme
 	}
 	return "any"
 }
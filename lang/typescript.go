@@ -11,6 +11,8 @@ import (
 
 type TypeScript struct {
 	builder strings.Builder
+	objects []cge.Object
+	aliases map[string]cge.Object
 }
 
 func (g *TypeScript) Generate(metadata cge.Metadata, objects []cge.Object, dir string) error {
@@ -21,6 +23,8 @@ func (g *TypeScript) Generate(metadata cge.Metadata, objects []cge.Object, dir s
 	defer file.Close()
 
 	g.builder = strings.Builder{}
+	g.objects = objects
+	g.aliases = aliasesByName(objects)
 
 	if len(metadata.Comments) > 0 {
 		g.builder.WriteString("/*\n")
@@ -30,78 +34,221 @@ func (g *TypeScript) Generate(metadata cge.Metadata, objects []cge.Object, dir s
 		g.builder.WriteString(" */\n\n")
 	}
 
+	g.generateFingerprint(metadata, objects)
+
 	eventNames := make([]string, 0)
+	scopes := make(map[string][]string)
 	commandNames := make([]string, 0)
+	responseCommandNames := make([]string, 0)
+	constants := make([]cge.Object, 0)
+	defaults := make([]cge.Object, 0)
 	for _, object := range objects {
+		if (object.Type == cge.CONFIG || object.Type == cge.COMMAND) && hasDefaults(object.Properties) {
+			// Default values are generated after all other objects, because they might reference enums.
+			defaults = append(defaults, object)
+		}
 		if object.Type == cge.CONFIG {
 			g.generateConfig(object)
 		} else if object.Type == cge.COMMAND {
 			g.generateCommand(object)
 			commandNames = append(commandNames, object.Name.Lexeme)
+			if len(object.Responses) > 0 {
+				responseCommandNames = append(responseCommandNames, object.Name.Lexeme)
+			}
 		} else if object.Type == cge.EVENT {
 			g.generateEvent(object)
 			eventNames = append(eventNames, object.Name.Lexeme)
+			if object.Scope.Lexeme != "" {
+				scopes[object.Scope.Lexeme] = append(scopes[object.Scope.Lexeme], object.Name.Lexeme)
+			}
 		} else if object.Type == cge.TYPE {
 			g.generateType(object)
+		} else if object.Type == cge.ALIAS {
+			g.generateAlias(object)
+		} else if object.Type == cge.UNION {
+			g.generateUnion(object)
+		} else if object.Type == cge.CONST {
+			// Constants are generated after all other objects, because they might reference enums.
+			constants = append(constants, object)
+			continue
 		} else {
 			g.generateEnum(object)
 		}
 		g.builder.WriteString("\n")
 	}
 
+	for _, object := range defaults {
+		g.generateDefaults(object)
+		g.builder.WriteString("\n")
+	}
+
+	for _, constant := range constants {
+		g.generateConstant(constant)
+		g.builder.WriteString("\n")
+	}
+
 	g.generateUnionTypes(commandNames, eventNames)
+	g.generateEventScopes(scopes)
+	g.generateCommandResponses(responseCommandNames)
 
 	file.WriteString(g.builder.String())
 
 	return nil
 }
 
+func (g *TypeScript) generateFingerprint(metadata cge.Metadata, objects []cge.Object) {
+	g.generateComments("", fingerprintComments, nil)
+	g.builder.WriteString(fmt.Sprintf("export const SCHEMA_FINGERPRINT = \"%s\";\n\n", cge.Fingerprint(metadata, objects)))
+}
+
+func (g *TypeScript) generateConstant(object cge.Object) {
+	g.generateComments("", object.Comments, object.Deprecated)
+	g.builder.WriteString(fmt.Sprintf("export const %s: %s = %s;\n", snakeToUppercase(object.Name.Lexeme), g.tsType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, nil, nil, nil), g.tsLiteral(*object.Value, object.ValueType)))
+}
+
 func (g *TypeScript) generateConfig(object cge.Object) {
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString("export interface GameConfig {\n")
 	g.generateProperties(object.Properties, 1, true)
 	g.builder.WriteString("}\n")
+	g.generateValidate("validateGameConfig", "GameConfig", object, true)
 }
 
 func (g *TypeScript) generateCommand(object cge.Object) {
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("export interface %sCmd {\n", snakeToPascal(object.Name.Lexeme)))
 	if len(object.Properties) > 0 {
-		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data: {\n", object.Name.Lexeme))
+		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data: %s{\n", object.Name.Lexeme, g.dataBase(object)))
 		g.generateProperties(object.Properties, 2, false)
 		g.builder.WriteString("  },\n")
+	} else if object.Extends != nil {
+		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data: %s,\n", object.Name.Lexeme, snakeToPascal(object.Extends.Token.Lexeme)))
 	} else {
 		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data?: undefined,\n", object.Name.Lexeme))
 	}
 	g.builder.WriteString("}\n")
+	g.generateValidate("validate"+snakeToPascal(object.Name.Lexeme)+"Cmd", snakeToPascal(object.Name.Lexeme)+"Cmd[\"data\"]", object, false)
+	g.generateSendAndWait(object)
+}
+
+// generateSendAndWait generates the union of all response events of a command
+// and the type of a function which sends the command and resolves with its response.
+func (g *TypeScript) generateSendAndWait(object cge.Object) {
+	if len(object.Responses) == 0 {
+		return
+	}
+
+	name := snakeToPascal(object.Name.Lexeme)
+	eventNames := make([]string, len(object.Responses))
+	eventTypes := make([]string, len(object.Responses))
+	for i, r := range object.Responses {
+		eventNames[i] = r.Lexeme
+		eventTypes[i] = snakeToPascal(r.Lexeme) + "Event"
+	}
+
+	g.builder.WriteString(fmt.Sprintf("\nexport type %sResponse = %s;\n", name, strings.Join(eventTypes, " | ")))
+	g.builder.WriteString("\n/**\n")
+	g.builder.WriteString(fmt.Sprintf(" * Sends a %s command and resolves with the first %s event.\n", object.Name.Lexeme, strings.Join(eventNames, " or ")))
+	g.builder.WriteString(" */\n")
+	g.builder.WriteString(fmt.Sprintf("export type Send%sAndWait = (data: %sCmd[\"data\"]) => Promise<%sResponse>;\n", name, name, name))
+}
+
+// generateEventScopes generates a union of all events for every event scope which is used by at least one event.
+func (g *TypeScript) generateEventScopes(scopes map[string][]string) {
+	if len(scopes) == 0 {
+		return
+	}
+
+	g.builder.WriteString("\n")
+	for _, scope := range cge.EventScopes {
+		if len(scopes[scope]) == 0 {
+			continue
+		}
+		events := make([]string, len(scopes[scope]))
+		for i, e := range scopes[scope] {
+			events[i] = snakeToPascal(e) + "Event"
+		}
+		g.builder.WriteString(fmt.Sprintf("export type %sEvents = %s;\n", snakeToPascal(scope), strings.Join(events, " | ")))
+	}
+}
+
+// generateCommandResponses maps the names of all commands with responses to their response types.
+func (g *TypeScript) generateCommandResponses(commandNames []string) {
+	if len(commandNames) == 0 {
+		return
+	}
+
+	g.builder.WriteString("\nexport interface CommandResponses {\n")
+	for _, c := range commandNames {
+		g.builder.WriteString(fmt.Sprintf("  %s: %sResponse,\n", c, snakeToPascal(c)))
+	}
+	g.builder.WriteString("}\n")
+	g.builder.WriteString("\n/**\n")
+	g.builder.WriteString(" * Sends a command and resolves with the first of its response events.\n")
+	g.builder.WriteString(" */\n")
+	g.builder.WriteString("export type SendAndWait = <C extends keyof CommandResponses>(name: C, data: Extract<Commands, { name: C }>[\"data\"]) => Promise<CommandResponses[C]>;\n")
 }
 
 func (g *TypeScript) generateEvent(object cge.Object) {
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("export interface %sEvent {\n", snakeToPascal(object.Name.Lexeme)))
 	if len(object.Properties) > 0 {
-		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data: {\n", object.Name.Lexeme))
+		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data: %s{\n", object.Name.Lexeme, g.dataBase(object)))
 		g.generateProperties(object.Properties, 2, false)
 		g.builder.WriteString("  },\n")
+	} else if object.Extends != nil {
+		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data: %s,\n", object.Name.Lexeme, snakeToPascal(object.Extends.Token.Lexeme)))
 	} else {
 		g.builder.WriteString(fmt.Sprintf("  name: \"%s\",\n  data?: undefined,\n", object.Name.Lexeme))
 	}
 	g.builder.WriteString("}\n")
+	g.generateValidate("validate"+snakeToPascal(object.Name.Lexeme)+"Event", snakeToPascal(object.Name.Lexeme)+"Event[\"data\"]", object, false)
+}
+
+// dataBase returns the intersection prefix for the data of an event or command which extends a type.
+func (g *TypeScript) dataBase(object cge.Object) string {
+	if object.Extends == nil {
+		return ""
+	}
+	return snakeToPascal(object.Extends.Token.Lexeme) + " & "
 }
 
 func (g *TypeScript) generateType(object cge.Object) {
-	g.generateComments("", object.Comments)
-	g.builder.WriteString(fmt.Sprintf("export interface %s {\n", snakeToPascal(object.Name.Lexeme)))
+	typeParameters := typeParameterList(object.TypeParameters)
+	g.generateComments("", object.Comments, object.Deprecated)
+	if object.Extends != nil {
+		g.builder.WriteString(fmt.Sprintf("export interface %s%s extends %s {\n", snakeToPascal(object.Name.Lexeme), typeParameters, snakeToPascal(object.Extends.Token.Lexeme)))
+	} else {
+		g.builder.WriteString(fmt.Sprintf("export interface %s%s {\n", snakeToPascal(object.Name.Lexeme), typeParameters))
+	}
 	g.generateProperties(object.Properties, 1, false)
 	g.builder.WriteString("}\n")
+	g.generateValidate("validate"+snakeToPascal(object.Name.Lexeme)+typeParameters, snakeToPascal(object.Name.Lexeme)+typeParameters, object, false)
+}
+
+func (g *TypeScript) generateAlias(object cge.Object) {
+	g.generateComments("", object.Comments, object.Deprecated)
+	tsType := g.tsType(object.ValueType.Token.Type, object.ValueType.Token.Lexeme, object.ValueType.Generic, object.ValueType.Key, object.ValueType.TypeArguments)
+	switch object.ValueType.Token.Type {
+	case cge.STRING, cge.BOOL, cge.INT32, cge.INT64, cge.FLOAT32, cge.FLOAT64, cge.UINT32, cge.UINT64, cge.BYTES, cge.TIMESTAMP, cge.DURATION, cge.UUID:
+		// Primitive aliases are branded to make them incompatible with other aliases of the same primitive.
+		g.builder.WriteString(fmt.Sprintf("export type %s = %s & { readonly __brand: \"%s\" };\n", snakeToPascal(object.Name.Lexeme), tsType, object.Name.Lexeme))
+	default:
+		g.builder.WriteString(fmt.Sprintf("export type %s = %s;\n", snakeToPascal(object.Name.Lexeme), tsType))
+	}
 }
 
 func (g *TypeScript) generateEnum(object cge.Object) {
-	g.generateComments("", object.Comments)
+	g.generateComments("", object.Comments, object.Deprecated)
 	g.builder.WriteString(fmt.Sprintf("export enum %s {\n", snakeToPascal(object.Name.Lexeme)))
+	values := object.EnumValues()
 	for i, p := range object.Properties {
-		g.generateComments("  ", p.Comments)
-		g.builder.WriteString(fmt.Sprintf("  %s = \"%s\"", snakeToUppercase(p.Name), p.Name))
+		value := values[i]
+		if !object.IsIntEnum() {
+			value = quoteString(value)
+		}
+		g.generateComments("  ", p.Comments, p.Deprecated)
+		g.builder.WriteString(fmt.Sprintf("  %s = %s", snakeToUppercase(p.Name), value))
 		if i < len(object.Properties)-1 {
 			g.builder.WriteString(",")
 		}
@@ -110,24 +257,114 @@ func (g *TypeScript) generateEnum(object cge.Object) {
 	g.builder.WriteString("}\n")
 }
 
+func (g *TypeScript) generateUnion(object cge.Object) {
+	g.generateComments("", object.Comments, object.Deprecated)
+	g.builder.WriteString(fmt.Sprintf("export type %s =", snakeToPascal(object.Name.Lexeme)))
+	if len(object.Properties) == 0 {
+		g.builder.WriteString(" never;\n")
+		return
+	}
+	for _, member := range object.Properties {
+		g.builder.WriteString("\n")
+		g.generateComments("  ", member.Comments, member.Deprecated)
+		g.builder.WriteString(fmt.Sprintf("  | ({ %s: \"%s\" } & %s)", object.Discriminator.Lexeme, member.Name, snakeToPascal(member.Name)))
+	}
+	g.builder.WriteString(";\n")
+}
+
 func (g *TypeScript) generateProperties(properties []cge.Property, indentSize int, optional bool) {
 	indent := strings.Repeat("  ", indentSize)
 	for _, property := range properties {
-		g.generateComments(indent, property.Comments)
+		g.generateComments(indent, property.Comments, property.Deprecated)
 		var questionMark string
-		if optional {
+		if optional || property.Optional || property.Default != nil {
 			questionMark = "?"
 		}
-		g.builder.WriteString(fmt.Sprintf("%s%s%s: %s,\n", indent, property.Name, questionMark, g.tsType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic)))
+		g.builder.WriteString(fmt.Sprintf("%s%s%s: %s,\n", indent, property.Name, questionMark, g.tsType(property.Type.Token.Type, property.Type.Token.Lexeme, property.Type.Generic, property.Type.Key, property.Type.TypeArguments)))
+	}
+}
+
+// generateDefaults generates a constant with the default values of a config or command.
+func (g *TypeScript) generateDefaults(object cge.Object) {
+	name, typeName := "gameConfigDefaults", "GameConfig"
+	if object.Type == cge.COMMAND {
+		name = snakeToCamel(object.Name.Lexeme) + "CmdDefaults"
+		typeName = fmt.Sprintf("Partial<%sCmd[\"data\"]>", snakeToPascal(object.Name.Lexeme))
+	}
+
+	g.builder.WriteString(fmt.Sprintf("export const %s: %s = {\n", name, typeName))
+	for _, property := range object.Properties {
+		if property.Default == nil {
+			continue
+		}
+		g.builder.WriteString(fmt.Sprintf("  %s: %s,\n", property.Name, g.tsLiteral(*property.Default, property.Type)))
+	}
+	g.builder.WriteString("};\n")
+}
+
+// generateValidate generates a function which returns the error message of the first violated constraint or null if the value is valid.
+// All properties are treated as optional if optional is true.
+func (g *TypeScript) generateValidate(name, typeName string, object cge.Object, optional bool) {
+	baseHasConstraints := baseHasConstraints(g.objects, object)
+	if !hasConstraints(object.Properties) && !baseHasConstraints {
+		return
+	}
+
+	g.builder.WriteString(fmt.Sprintf("\nexport function %s(value: %s): string | null {\n", name, typeName))
+	if baseHasConstraints {
+		g.builder.WriteString(fmt.Sprintf("  const baseError = validate%s(value);\n", snakeToPascal(object.Extends.Token.Lexeme)))
+		g.builder.WriteString("  if (baseError !== null) {\n")
+		g.builder.WriteString("    return baseError;\n")
+		g.builder.WriteString("  }\n")
+	}
+	for _, property := range object.Properties {
+		field := "value." + property.Name
+		guard := ""
+		if optional || property.Optional || property.Default != nil {
+			guard = field + " !== undefined && "
+		}
+		for _, constraint := range property.Constraints {
+			var condition string
+			switch constraint.Name.Lexeme {
+			case "min":
+				condition = fmt.Sprintf("%s < %s", field, constraint.Value.Value)
+			case "max":
+				condition = fmt.Sprintf("%s > %s", field, constraint.Value.Value)
+			case "min_len":
+				condition = fmt.Sprintf("Array.from(%s).length < %s", field, constraint.Value.Value)
+			case "max_len":
+				condition = fmt.Sprintf("Array.from(%s).length > %s", field, constraint.Value.Value)
+			case "pattern":
+				condition = fmt.Sprintf("!new RegExp(%s).test(%s)", quoteString(constraint.Value.Value), field)
+			case "min_items", "max_items":
+				length := field + ".length"
+				if property.Type.Token.Type == cge.MAP {
+					length = fmt.Sprintf("Object.keys(%s).length", field)
+				}
+				operator := "<"
+				if constraint.Name.Lexeme == "max_items" {
+					operator = ">"
+				}
+				condition = fmt.Sprintf("%s %s %s", length, operator, constraint.Value.Value)
+			}
+			g.builder.WriteString(fmt.Sprintf("  if (%s%s) {\n", guard, condition))
+			g.builder.WriteString(fmt.Sprintf("    return %s;\n", quoteString(constraintMessage(property, constraint))))
+			g.builder.WriteString("  }\n")
+		}
 	}
+	g.builder.WriteString("  return null;\n")
+	g.builder.WriteString("}\n")
 }
 
-func (g *TypeScript) generateComments(indent string, comments []string) {
-	if len(comments) != 0 {
+func (g *TypeScript) generateComments(indent string, comments []string, deprecation *cge.Deprecation) {
+	if len(comments) != 0 || deprecation != nil {
 		g.builder.WriteString(indent + "/**\n")
 		for _, comment := range comments {
 			g.builder.WriteString(indent + " * " + comment + "\n")
 		}
+		if deprecation != nil {
+			g.builder.WriteString(indent + " * @deprecated " + deprecationReason(deprecation) + "\n")
+		}
 		g.builder.WriteString(indent + " */\n")
 	}
 }
@@ -154,7 +391,7 @@ func (g *TypeScript) generateUnionTypes(commandNames, eventNames []string) {
 	}
 }
 
-func (g *TypeScript) tsType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType) string {
+func (g *TypeScript) tsType(tokenType cge.TokenType, lexeme string, generic *cge.PropertyType, key *cge.PropertyType, typeArguments []*cge.PropertyType) string {
 	switch tokenType {
 	case cge.STRING:
 		return "string"
@@ -168,12 +405,58 @@ func (g *TypeScript) tsType(tokenType cge.TokenType, lexeme string, generic *cge
 		return "number"
 	case cge.FLOAT64:
 		return "number"
+	case cge.UINT32, cge.UINT64:
+		return "number"
+	// The following types use their JSON wire encoding, because the data is not converted after parsing.
+	case cge.BYTES:
+		// base64
+		return "string"
+	case cge.TIMESTAMP:
+		// RFC 3339
+		return "string"
+	case cge.DURATION:
+		// nanoseconds
+		return "number"
+	case cge.UUID:
+		return "string"
 	case cge.LIST:
-		return g.tsType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + "[]"
+		return g.tsType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments) + "[]"
 	case cge.MAP:
-		return "{ [index: string]: " + g.tsType(generic.Token.Type, generic.Token.Lexeme, generic.Generic) + " }"
+		valueType := g.tsType(generic.Token.Type, generic.Token.Lexeme, generic.Generic, generic.Key, generic.TypeArguments)
+		if key == nil || key.Token.Type == cge.STRING {
+			return "{ [index: string]: " + valueType + " }"
+		}
+		keyType := g.tsType(key.Token.Type, key.Token.Lexeme, nil, nil, nil)
+		// maps with enum keys don't need to contain every enum value
+		if resolveAlias(g.aliases, key).Token.Type == cge.IDENTIFIER {
+			return "Partial<Record<" + keyType + ", " + valueType + ">>"
+		}
+		return "Record<" + keyType + ", " + valueType + ">"
 	case cge.IDENTIFIER:
+		if len(typeArguments) > 0 {
+			arguments := make([]string, len(typeArguments))
+			for i, a := range typeArguments {
+				arguments[i] = g.tsType(a.Token.Type, a.Token.Lexeme, a.Generic, a.Key, a.TypeArguments)
+			}
+			return snakeToPascal(lexeme) + "<" + strings.Join(arguments, ", ") + ">"
+		}
+		return snakeToPascal(lexeme)
+	case cge.TYPE_PARAMETER:
 		return snakeToPascal(lexeme)
 	}
 	return "any"
 }
+
+func (g *TypeScript) tsLiteral(literal cge.Literal, propertyType *cge.PropertyType) string {
+	if _, ok := g.aliases[propertyType.Token.Lexeme]; ok && literal.Token.Type != cge.IDENTIFIER {
+		return fmt.Sprintf("%s as %s", g.tsLiteral(literal, resolveAlias(g.aliases, propertyType)), snakeToPascal(propertyType.Token.Lexeme))
+	}
+
+	switch literal.Token.Type {
+	case cge.STRING_LITERAL:
+		return quoteString(literal.Value)
+	case cge.IDENTIFIER:
+		return snakeToPascal(resolveAlias(g.aliases, propertyType).Token.Lexeme) + "." + snakeToUppercase(literal.Value)
+	}
+	return literal.Value
+}